@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/bot/fake"
+	"drillCore/internal/config"
+	accessMemory "drillCore/internal/storage/access/memory"
+	"drillCore/internal/storage/debt/instrumented"
+	"drillCore/internal/storage/debt/memory"
+	updateMemory "drillCore/internal/storage/update/memory"
+	userMemory "drillCore/internal/storage/user/memory"
+
+	"go.uber.org/zap"
+)
+
+// demoUserID — the only user of the demo, admin commands are allowed for them
+const demoUserID = 1
+
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// runDemo — `app demo`: the bot on the in-memory storage and the fake telegram server,
+// chatting through the terminal. No env variables, database or token are needed.
+func runDemo(ctx context.Context) error {
+	logger, err := setUpLogger(&config.AppEnvs{Env: "local"})
+	if err != nil {
+		return err
+	}
+	logger = logger.Desugar().WithOptions(zap.IncreaseLevel(zap.WarnLevel)).Sugar()
+
+	srv := fake.New()
+	defer srv.Close()
+
+	cfg := &config.ServiceConfig{
+		AppEnvs: &config.AppEnvs{
+			Env:            "local",
+			AdminIDs:       []int{demoUserID},
+			UndoWindow:     5 * time.Minute,
+			RateLimit:      30,
+			RateWindow:     time.Minute,
+			RetryAttempts:  2,
+			RetryBackoff:   100 * time.Millisecond,
+			BroadcastRate:  25,
+			BroadcastBatch: 100,
+		},
+		TelegramEnvs: &config.TelegramEnvs{
+			Token:     "demo",
+			BaseUrl:   srv.URL(),
+			BatchSize: 10,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- run(ctx, cfg, instrumented.New(memory.New(logger)), updateMemory.New(), accessMemory.New(), userMemory.New(), logger)
+	}()
+
+	var (
+		mu      sync.Mutex
+		buttons []bot.InlineKeyboardButton
+	)
+
+	go func() {
+		for msg := range srv.Messages() {
+			mu.Lock()
+			buttons = printMessage(msg)
+			mu.Unlock()
+		}
+	}()
+
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(os.Stdin)
+		for sc.Scan() {
+			lines <- strings.TrimSpace(sc.Text())
+		}
+		cancel()
+	}()
+
+	fmt.Println("demo mode: type a message, #N presses button N, ctrl+d exits")
+	srv.SendText(demoUserID, "/start")
+
+	for {
+		select {
+		case err := <-errc:
+			return err
+
+		case line := <-lines:
+			if line == "" {
+				continue
+			}
+
+			if n, err := strconv.Atoi(strings.TrimPrefix(line, "#")); err == nil && strings.HasPrefix(line, "#") {
+				mu.Lock()
+				ok := n >= 1 && n <= len(buttons)
+				var data string
+				if ok {
+					data = buttons[n-1].CallbackData
+				}
+				mu.Unlock()
+
+				if !ok {
+					fmt.Println("no such button")
+					continue
+				}
+
+				srv.Press(demoUserID, data)
+				continue
+			}
+
+			srv.SendText(demoUserID, line)
+		}
+	}
+}
+
+// printMessage — prints the bot message and returns its inline buttons in the printed order
+func printMessage(msg fake.Message) []bot.InlineKeyboardButton {
+	text := msg.Text
+	if msg.ParseMode == bot.ParseModeHTML {
+		text = html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+	}
+
+	fmt.Printf("\n%s\n", text)
+
+	var res []bot.InlineKeyboardButton
+	for _, row := range msg.Markup.InlineKeyboard {
+		cells := make([]string, 0, len(row))
+		for _, b := range row {
+			res = append(res, b)
+			cells = append(cells, fmt.Sprintf("[#%d %s]", len(res), b.Text))
+		}
+
+		fmt.Println(strings.Join(cells, " "))
+	}
+
+	return res
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"drillCore/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+const pingTimeout = 2 * time.Second
+
+// readiness — checks behind /readyz
+type readiness struct {
+	storage interface {
+		Ping(ctx context.Context) error
+	}
+	poller   interface{ LastPoll() time.Time }
+	consumer interface{ LastBeat() time.Time }
+
+	pollWindow  time.Duration
+	stallWindow time.Duration
+}
+
+// check — failed checks, empty when the bot is ready
+func (r *readiness) check(ctx context.Context) []string {
+	var failed []string
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if err := r.storage.Ping(pingCtx); err != nil {
+		failed = append(failed, fmt.Sprintf("storage: %v", err))
+	}
+
+	if age := time.Since(r.poller.LastPoll()); age > r.pollWindow {
+		failed = append(failed, fmt.Sprintf("polling: last successful getUpdates %s ago", age.Truncate(time.Second)))
+	}
+
+	if age := time.Since(r.consumer.LastBeat()); age > r.stallWindow {
+		failed = append(failed, fmt.Sprintf("consumer: no progress for %s", age.Truncate(time.Second)))
+	}
+
+	return failed
+}
+
+func (r *readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if failed := r.check(req.Context()); len(failed) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, strings.Join(failed, "\n"))
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// serveHTTP — serves /metrics, /healthz and /readyz on addr until ctx is done
+func serveHTTP(ctx context.Context, addr string, ready *readiness, logger *zap.SugaredLogger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	mux.Handle("/readyz", ready)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Warnw("failed to shutdown http server", "error", err)
+		}
+	}()
+
+	logger.Infof("serving http on %s", addr)
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorw("http server stopped", "error", err)
+	}
+}
@@ -2,23 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"drillCore/internal/access"
 	"drillCore/internal/bot"
+	"drillCore/internal/broadcast"
 	"drillCore/internal/config"
 	"drillCore/internal/events/event-consummer"
 	"drillCore/internal/events/event-processor"
 	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/events/event-processor/manager/admin"
 	"drillCore/internal/events/event-processor/manager/command"
 	"drillCore/internal/events/event-processor/manager/date"
 	"drillCore/internal/events/event-processor/manager/debt"
 	mainmenu "drillCore/internal/events/event-processor/manager/main-menu"
+	"drillCore/internal/events/event-processor/manager/profile"
+	"drillCore/internal/metrics"
+	"drillCore/internal/ratelimit"
 	"drillCore/internal/session"
-	"drillCore/internal/storage/debt/postgres"
+	"drillCore/internal/tracing"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -28,6 +35,24 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	if len(os.Args) > 1 && os.Args[1] == "demo" {
+		if err := runDemo(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(ctx, os.Args[2:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	cfg, err := config.New()
 	if err != nil {
 		fmt.Println(err)
@@ -42,28 +67,101 @@ func main() {
 
 	logger.Debugf("resived config: %+v", cfg)
 
-	storage, err := postgres.New(ctx, cfg.DbEnvs, logger)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.AppEnvs)
+	if err != nil {
+		logger.Fatalf("failed to init tracing: %v", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warnw("failed to flush traces", "error", err)
+		}
+	}()
+
+	storage, err := openStorage(ctx, cfg.DbEnvs, logger)
+	if err != nil {
+		logger.Fatalf("failed to init storage: %v", err)
+	}
+	defer storage.close()
+
+	if cfg.DbEnvs.AutoMigrate {
+		if err := storage.migrator.Up(ctx); err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
+	if err := storage.migrator.Check(ctx); err != nil {
+		logger.Fatalf("refusing to start: %v", err)
+	}
+
+	if err := run(ctx, cfg, storage.debts, storage.updates, storage.access, storage.users, logger); err != nil && !errors.Is(err, context.Canceled) {
+		logger.Fatalf("service stopped:%v", err)
+	}
+}
 
+// run — wires the bot on top of the storages and blocks until ctx is done
+func run(ctx context.Context, cfg *config.ServiceConfig, storage appStorage, updates appUpdates, rules access.Storage, users appUsers, logger *zap.SugaredLogger) error {
 	tg := bot.New(cfg.TelegramEnvs, logger)
 
+	guard := access.New(rules, cfg.AppEnvs.AdminIDs, cfg.AppEnvs.AllowedIDs, cfg.AppEnvs.Allowlist, logger)
+	if err := guard.Load(ctx); err != nil {
+		return err
+	}
+
+	if err := tg.SetMyCommands(ctx, manager.BotCommands()); err != nil {
+		logger.Warnw("failed to register bot commands", "error", err)
+	}
+
 	sMng := session.New()
+	metrics.ObserveSessions(sMng.Len)
 
-	debtH := debt.New(tg, sMng, storage, logger)
+	debtH := debt.New(tg, sMng, storage, cfg.AppEnvs.UndoWindow, logger)
 	cmdH := command.New(tg, sMng, logger)
 	menuH := mainmenu.New(tg, sMng, logger)
 	dateH := date.New(tg, sMng, logger)
+	profileH := profile.New(tg, users, storage, logger)
+	broadcasts := broadcast.New(tg, users, guard, cfg.AppEnvs.BroadcastRate, cfg.AppEnvs.BroadcastBatch, logger)
 
-	hMng := manager.New(tg, sMng, logger, cmdH, menuH, debtH, dateH)
+	adminH := admin.New(tg, sMng, storage, updates, users, guard, broadcasts, logger)
 
-	eventsProcessor := eventprocessor.New(tg, hMng, logger)
+	limiter := ratelimit.New(cfg.AppEnvs.RateLimit, cfg.AppEnvs.RateWindow)
+	metrics.ObserveRateLimited(limiter.Len)
+
+	middlewares := []manager.Middleware{
+		manager.Logging(logger),
+		manager.Settings(storage, logger, manager.DebtHandler),
+	}
+
+	hMng := manager.New(tg, sMng, logger, middlewares, cmdH, menuH, debtH, dateH, adminH, profileH)
+
+	eventsProcessor := eventprocessor.New(tg, hMng, updates, guard, limiter, users, logger)
 
 	logger.Info("Starting event-processor bot")
 
-	consumer := eventconsummer.New(eventsProcessor, eventsProcessor, cfg.TelegramEnvs.BatchSize, logger)
-	if err := consumer.Start(ctx); err != nil {
-		logger.Fatalf("service stopped:%v", err)
+	retry := eventconsummer.Retry{Attempts: cfg.AppEnvs.RetryAttempts, Backoff: cfg.AppEnvs.RetryBackoff}
+	consumer := eventconsummer.New(eventsProcessor, eventsProcessor, eventsProcessor, cfg.TelegramEnvs.BatchSize, retry, logger)
+
+	if cfg.AppEnvs.HTTPAddr != "" {
+		ready := &readiness{
+			storage:     storage,
+			poller:      eventsProcessor,
+			consumer:    &consumer,
+			pollWindow:  cfg.AppEnvs.ReadyPollWindow,
+			stallWindow: cfg.AppEnvs.ReadyStallWindow,
+		}
+
+		go serveHTTP(ctx, cfg.AppEnvs.HTTPAddr, ready, logger)
 	}
 
+	go func() {
+		if err := broadcasts.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Errorw("broadcast job stopped", "error", err)
+		}
+	}()
+
+	return consumer.Start(ctx)
 }
 
 func setUpLogger(cfg *config.AppEnvs) (*zap.SugaredLogger, error) {
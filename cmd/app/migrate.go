@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"drillCore/internal/config"
+)
+
+var errUnknownMigrateCommand = errors.New("unknown migrate command, expected up/down/status")
+
+// runMigrateCommand — `app migrate up|down|status`, needs only the database config
+func runMigrateCommand(ctx context.Context, args []string) error {
+	db, err := config.NewDB()
+	if err != nil {
+		return err
+	}
+
+	logger, err := setUpLogger(&config.AppEnvs{Env: "local"})
+	if err != nil {
+		return err
+	}
+
+	storage, err := openStorage(ctx, db, logger)
+	if err != nil {
+		return fmt.Errorf("failed to init storage: %w", err)
+	}
+	defer storage.close()
+
+	if err := runMigrate(ctx, storage.migrator, args); err != nil {
+		return fmt.Errorf("migrate failed: %w", err)
+	}
+
+	return nil
+}
+
+// runMigrate — applies the migrate subcommand to m
+func runMigrate(ctx context.Context, m migrator, args []string) error {
+	if len(args) == 0 {
+		return errUnknownMigrateCommand
+	}
+
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+
+	case "down":
+		return m.Down(ctx)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, st := range statuses {
+			appliedAt := "pending"
+			if st.Applied {
+				appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", st.Migration.Version, st.Migration.Name, appliedAt)
+		}
+
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("%w: %s", errUnknownMigrateCommand, args[0])
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"drillCore/internal/access"
+	"drillCore/internal/broadcast"
+	"drillCore/internal/config"
+	"drillCore/internal/events/event-processor"
+	"drillCore/internal/events/event-processor/manager/admin"
+	"drillCore/internal/events/event-processor/manager/debt"
+	"drillCore/internal/events/event-processor/manager/profile"
+	accessPostgres "drillCore/internal/storage/access/postgres"
+	accessSQLite "drillCore/internal/storage/access/sqlite"
+	"drillCore/internal/storage/debt/instrumented"
+	"drillCore/internal/storage/debt/postgres"
+	"drillCore/internal/storage/debt/sqlite"
+	"drillCore/internal/storage/migrate"
+	"drillCore/internal/storage/pg"
+	"drillCore/internal/storage/sqlitedb"
+	updateInstrumented "drillCore/internal/storage/update/instrumented"
+	updatePostgres "drillCore/internal/storage/update/postgres"
+	updateSQLite "drillCore/internal/storage/update/sqlite"
+	userInstrumented "drillCore/internal/storage/user/instrumented"
+	userPostgres "drillCore/internal/storage/user/postgres"
+	userSQLite "drillCore/internal/storage/user/sqlite"
+	"drillCore/migrations"
+
+	"go.uber.org/zap"
+)
+
+// appStorage — everything the handlers need from the storage backend
+type appStorage interface {
+	debt.Storage
+	admin.Storage
+	Ping(ctx context.Context) error
+}
+
+// appUpdates — polling state and dead letters of the storage backend
+type appUpdates interface {
+	eventprocessor.UpdateStorage
+	admin.DeadLetters
+}
+
+// appUsers — users registry and broadcasts of the storage backend
+type appUsers interface {
+	eventprocessor.Registry
+	admin.Users
+	profile.Users
+	broadcast.Storage
+}
+
+type migrator interface {
+	Up(ctx context.Context) error
+	Down(ctx context.Context) error
+	Status(ctx context.Context) ([]*migrate.Status, error)
+	Check(ctx context.Context) error
+}
+
+// backend — storages of the configured driver sharing one connection, close releases it
+type backend struct {
+	debts    appStorage
+	updates  appUpdates
+	access   access.Storage
+	users    appUsers
+	migrator migrator
+	close    func()
+}
+
+// openStorage — opens the backend of the configured driver
+func openStorage(ctx context.Context, cfg *config.DbEnvs, logger *zap.SugaredLogger) (*backend, error) {
+	switch cfg.Driver {
+	case config.DriverSQLite:
+		db, err := sqlitedb.Open(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := migrate.NewSQLite(db, migrations.SQLite(), logger)
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to load migrations: %w", err)
+		}
+
+		return &backend{
+			debts:    instrumented.New(sqlite.New(db, logger)),
+			updates:  updateInstrumented.New(updateSQLite.New(db, logger)),
+			access:   accessSQLite.New(db, logger),
+			users:    userInstrumented.New(userSQLite.New(db, logger)),
+			migrator: m,
+			close:    func() { _ = db.Close() },
+		}, nil
+
+	default:
+		pool, err := pg.NewPool(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := migrate.New(pool, migrations.FS, logger)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to load migrations: %w", err)
+		}
+
+		return &backend{
+			debts:    instrumented.New(postgres.New(pool, logger)),
+			updates:  updateInstrumented.New(updatePostgres.New(pool, logger)),
+			access:   accessPostgres.New(pool, logger),
+			users:    userInstrumented.New(userPostgres.New(pool, logger)),
+			migrator: m,
+			close:    pool.Close,
+		}, nil
+	}
+}
@@ -0,0 +1,150 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"drillCore/internal/model"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBanned      = errors.New("user is banned")
+	ErrNotAllowed  = errors.New("user is not in the allowlist")
+	ErrAdminImmune = errors.New("admins can not be banned or revoked")
+)
+
+type Storage interface {
+	AccessRules(ctx context.Context) ([]*model.AccessRule, error)
+	SaveAccessRule(ctx context.Context, rule *model.AccessRule) error
+}
+
+// Guard — who may use the bot: admins and allowed IDs come from config, bans and
+// allowlist entries from the admin commands. Rules are cached, Check never hits the storage.
+type Guard struct {
+	mu sync.RWMutex
+
+	storage   Storage
+	admins    map[int]struct{}
+	allowed   map[int]struct{} // from config
+	allowlist bool             // private deployment, only admins and allowed users pass
+	rules     map[int]model.AccessRule
+
+	logger *zap.SugaredLogger
+}
+
+func New(storage Storage, adminIDs, allowedIDs []int, allowlist bool, logger *zap.SugaredLogger) *Guard {
+	return &Guard{
+		storage:   storage,
+		admins:    idSet(adminIDs),
+		allowed:   idSet(allowedIDs),
+		allowlist: allowlist,
+		rules:     make(map[int]model.AccessRule),
+		logger:    logger,
+	}
+}
+
+// Load — fills the cache from the storage, must be called before the first Check
+func (g *Guard) Load(ctx context.Context) error {
+	rules, err := g.storage.AccessRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load access rules: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.rules = make(map[int]model.AccessRule, len(rules))
+	for _, r := range rules {
+		g.rules[int(r.UserID)] = *r
+	}
+
+	return nil
+}
+
+// Check — nil when the user may use the bot, ErrBanned or ErrNotAllowed otherwise
+func (g *Guard) Check(userID int) error {
+	if g.IsAdmin(userID) {
+		return nil
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rule := g.rules[userID]
+	if rule.Banned {
+		return ErrBanned
+	}
+
+	if !g.allowlist {
+		return nil
+	}
+
+	if _, ok := g.allowed[userID]; ok || rule.Allowed {
+		return nil
+	}
+
+	return ErrNotAllowed
+}
+
+func (g *Guard) IsAdmin(userID int) bool {
+	_, ok := g.admins[userID]
+	return ok
+}
+
+// Allowlist — whether only allowed users pass
+func (g *Guard) Allowlist() bool {
+	return g.allowlist
+}
+
+func (g *Guard) Ban(ctx context.Context, adminID, userID int) error {
+	return g.change(ctx, adminID, userID, func(r *model.AccessRule) { r.Banned = true })
+}
+
+func (g *Guard) Unban(ctx context.Context, adminID, userID int) error {
+	return g.change(ctx, adminID, userID, func(r *model.AccessRule) { r.Banned = false })
+}
+
+func (g *Guard) Allow(ctx context.Context, adminID, userID int) error {
+	return g.change(ctx, adminID, userID, func(r *model.AccessRule) { r.Allowed = true })
+}
+
+func (g *Guard) Revoke(ctx context.Context, adminID, userID int) error {
+	return g.change(ctx, adminID, userID, func(r *model.AccessRule) { r.Allowed = false })
+}
+
+// change — saves the changed rule and updates the cache after the storage accepted it
+func (g *Guard) change(ctx context.Context, adminID, userID int, fn func(r *model.AccessRule)) error {
+	if g.IsAdmin(userID) {
+		return ErrAdminImmune
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rule := g.rules[userID]
+	rule.UserID = int64(userID)
+	rule.UpdatedBy = int64(adminID)
+	fn(&rule)
+
+	if err := g.storage.SaveAccessRule(ctx, &rule); err != nil {
+		return err
+	}
+
+	g.rules[userID] = rule
+	g.logger.Infow("access rule changed", "admin", adminID, "user_id", userID, "allowed", rule.Allowed, "banned", rule.Banned)
+
+	return nil
+}
+
+func idSet(ids []int) map[int]struct{} {
+	res := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		res[id] = struct{}{}
+	}
+
+	return res
+}
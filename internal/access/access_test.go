@@ -0,0 +1,155 @@
+package access_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"drillCore/internal/access"
+	"drillCore/internal/model"
+	"drillCore/internal/storage/access/memory"
+
+	"go.uber.org/zap"
+)
+
+const (
+	adminID      = 1
+	configUserID = 2 // allowed by the config
+	dbUserID     = 3 // allowed by an admin command before the start
+	strangerID   = 4
+)
+
+var errStorage = errors.New("storage is down")
+
+// flakyStorage — memory rules, SaveAccessRule fails while down is set
+type flakyStorage struct {
+	*memory.AccessStorage
+	down bool
+}
+
+func (s *flakyStorage) SaveAccessRule(ctx context.Context, rule *model.AccessRule) error {
+	if s.down {
+		return errStorage
+	}
+
+	return s.AccessStorage.SaveAccessRule(ctx, rule)
+}
+
+func newGuard(t *testing.T, storage access.Storage, allowlist bool) *access.Guard {
+	t.Helper()
+
+	g := access.New(storage, []int{adminID}, []int{configUserID}, allowlist, zap.NewNop().Sugar())
+	if err := g.Load(t.Context()); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	return g
+}
+
+func assertCheck(t *testing.T, g *access.Guard, userID int, want error) {
+	t.Helper()
+
+	if err := g.Check(userID); !errors.Is(err, want) {
+		t.Fatalf("check user %d: got %v, want %v", userID, err, want)
+	}
+}
+
+func TestAdminImmunity(t *testing.T) {
+	storage := memory.New()
+
+	// a rule written before the user became an admin does not lock them out
+	if err := storage.SaveAccessRule(t.Context(), &model.AccessRule{UserID: adminID, Banned: true}); err != nil {
+		t.Fatalf("save rule: %v", err)
+	}
+
+	g := newGuard(t, storage, true)
+	assertCheck(t, g, adminID, nil)
+
+	if err := g.Ban(t.Context(), adminID, adminID); !errors.Is(err, access.ErrAdminImmune) {
+		t.Fatalf("ban admin: got %v, want %v", err, access.ErrAdminImmune)
+	}
+	if err := g.Revoke(t.Context(), adminID, adminID); !errors.Is(err, access.ErrAdminImmune) {
+		t.Fatalf("revoke admin: got %v, want %v", err, access.ErrAdminImmune)
+	}
+
+	assertCheck(t, g, adminID, nil)
+}
+
+func TestAllowlist(t *testing.T) {
+	storage := memory.New()
+	if err := storage.SaveAccessRule(t.Context(), &model.AccessRule{UserID: dbUserID, Allowed: true}); err != nil {
+		t.Fatalf("save rule: %v", err)
+	}
+
+	g := newGuard(t, storage, true)
+
+	assertCheck(t, g, configUserID, nil)
+	assertCheck(t, g, dbUserID, nil)
+	assertCheck(t, g, strangerID, access.ErrNotAllowed)
+
+	if err := g.Allow(t.Context(), adminID, strangerID); err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+	assertCheck(t, g, strangerID, nil)
+
+	// the database entries are revoked, the config ones stay till the config changes
+	for _, id := range []int{dbUserID, strangerID, configUserID} {
+		if err := g.Revoke(t.Context(), adminID, id); err != nil {
+			t.Fatalf("revoke %d: %v", id, err)
+		}
+	}
+	assertCheck(t, g, dbUserID, access.ErrNotAllowed)
+	assertCheck(t, g, strangerID, access.ErrNotAllowed)
+	assertCheck(t, g, configUserID, nil)
+
+	// a ban beats the allowlist
+	if err := g.Ban(t.Context(), adminID, configUserID); err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+	assertCheck(t, g, configUserID, access.ErrBanned)
+}
+
+func TestOpenBot(t *testing.T) {
+	g := newGuard(t, memory.New(), false)
+
+	assertCheck(t, g, strangerID, nil)
+
+	if err := g.Ban(t.Context(), adminID, strangerID); err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+	assertCheck(t, g, strangerID, access.ErrBanned)
+
+	if err := g.Unban(t.Context(), adminID, strangerID); err != nil {
+		t.Fatalf("unban: %v", err)
+	}
+	assertCheck(t, g, strangerID, nil)
+}
+
+func TestChangeSavedBeforeCached(t *testing.T) {
+	storage := &flakyStorage{AccessStorage: memory.New(), down: true}
+	g := newGuard(t, storage, false)
+
+	if err := g.Ban(t.Context(), adminID, strangerID); !errors.Is(err, errStorage) {
+		t.Fatalf("ban: got %v, want %v", err, errStorage)
+	}
+
+	// the rule the storage rejected must not take effect, it would be lost on restart
+	assertCheck(t, g, strangerID, nil)
+
+	storage.down = false
+	if err := g.Ban(t.Context(), adminID, strangerID); err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+	assertCheck(t, g, strangerID, access.ErrBanned)
+
+	// and the saved one survives a restart
+	assertCheck(t, newGuard(t, storage, false), strangerID, access.ErrBanned)
+
+	rules, err := storage.AccessRules(t.Context())
+	if err != nil {
+		t.Fatalf("rules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].UpdatedBy != adminID {
+		t.Fatalf("got rules %+v, want one ban by the admin", rules)
+	}
+}
@@ -15,11 +15,16 @@ import (
 	"strconv"
 
 	"drillCore/internal/config"
+	"drillCore/internal/metrics"
+	"drillCore/internal/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type Client struct {
+	scheme   string
 	host     string
 	basePath string
 	client   http.Client
@@ -27,13 +32,23 @@ type Client struct {
 }
 
 const (
-	getUpdatesMethod  = "getUpdates"
-	sendMessageMethod = "sendMessage"
+	getUpdatesMethod    = "getUpdates"
+	sendMessageMethod   = "sendMessage"
+	sendPhotoMethod     = "sendPhoto"
+	setMyCommandsMethod = "setMyCommands"
 )
 
+// New — cfg.BaseUrl is the api host (api.telegram.org), or a full url like http://127.0.0.1:8081
+// for a local bot api server or a fake one
 func New(cfg *config.TelegramEnvs, logger *zap.SugaredLogger) *Client {
+	scheme, host := "https", cfg.BaseUrl
+	if u, err := url.Parse(cfg.BaseUrl); err == nil && u.Scheme != "" && u.Host != "" {
+		scheme, host = u.Scheme, u.Host
+	}
+
 	return &Client{
-		host:     cfg.BaseUrl,
+		scheme:   scheme,
+		host:     host,
 		basePath: newBasePath(cfg.Token),
 		client:   http.Client{},
 		logger:   logger,
@@ -67,11 +82,13 @@ func (c *Client) Updates(ctx context.Context, offset int, limit int) ([]Update,
 	return res.Result, nil
 }
 
-func (c *Client) SendMessage(ctx context.Context, chatID int, text string) error {
+func (c *Client) SendMessage(ctx context.Context, chatID int, text string, opts ...MessageOption) error {
 	q := url.Values{}
 	q.Add("chat_id", strconv.Itoa(chatID))
 	q.Add("text", text)
 
+	newMessageOptions(opts...).applyQuery(q)
+
 	_, err := c.doRequest(ctx, sendMessageMethod, q)
 	if err != nil {
 		return fmt.Errorf("failed to send message:%w", err)
@@ -80,45 +97,43 @@ func (c *Client) SendMessage(ctx context.Context, chatID int, text string) error
 	return nil
 }
 
-func (c *Client) SendMessageWithKeyboard(ctx context.Context, chatID int, text string, keyboard ReplyMarkup) error {
+func (c *Client) SendMessageWithKeyboard(ctx context.Context, chatID int, text string, keyboard ReplyMarkup, opts ...MessageOption) error {
+	o := newMessageOptions(opts...)
+
 	req := struct {
-		ChatID      int         `json:"chat_id"`
-		Text        string      `json:"text"`
-		ReplyMarkup ReplyMarkup `json:"reply_markup"`
+		ChatID              int                 `json:"chat_id"`
+		Text                string              `json:"text"`
+		ParseMode           ParseMode           `json:"parse_mode,omitempty"`
+		LinkPreviewOptions  *linkPreviewOptions `json:"link_preview_options,omitempty"`
+		DisableNotification bool                `json:"disable_notification,omitempty"`
+		ReplyMarkup         ReplyMarkup         `json:"reply_markup"`
 	}{
-		ChatID:      chatID,
-		Text:        text,
-		ReplyMarkup: keyboard,
+		ChatID:              chatID,
+		Text:                text,
+		ParseMode:           o.ParseMode,
+		LinkPreviewOptions:  o.linkPreview(),
+		DisableNotification: o.DisableNotification,
+		ReplyMarkup:         keyboard,
 	}
 
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("can't marshal request: %w", err)
-	}
+	return c.doJSONRequest(ctx, sendMessageMethod, req)
+}
 
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.host,
-		Path:   path.Join(c.basePath, sendMessageMethod),
+func (c *Client) SetMyCommands(ctx context.Context, commands []BotCommand) error {
+	req := struct {
+		Commands []BotCommand `json:"commands"`
+	}{
+		Commands: commands,
 	}
 
-	resp, err := c.client.Post(u.String(), "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("can't do request: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("event-processor error: %s", string(body))
+	if err := c.doJSONRequest(ctx, setMyCommandsMethod, req); err != nil {
+		return fmt.Errorf("failed to set commands: %w", err)
 	}
 
 	return nil
 }
 
-func (c *Client) SendPhotoWithKeyBoard(ctx context.Context, chatID int, photoPath, caption string, keyboard ReplyMarkup) error {
+func (c *Client) SendPhotoWithKeyBoard(ctx context.Context, chatID int, photoPath, caption string, keyboard ReplyMarkup, opts ...MessageOption) (err error) {
 	file, err := os.Open(photoPath)
 	if err != nil {
 		return fmt.Errorf("failed to open photo: %w", err)
@@ -141,7 +156,9 @@ func (c *Client) SendPhotoWithKeyBoard(ctx context.Context, chatID int, photoPat
 		_ = writer.WriteField("caption", caption)
 	}
 
-	if keyboard.InlineKeyboard != nil {
+	newMessageOptions(opts...).applyForm(writer)
+
+	if !keyboard.IsEmpty() {
 		kbData, err := json.Marshal(keyboard)
 		if err != nil {
 			return fmt.Errorf("failed to marshal keyboard: %w", err)
@@ -153,13 +170,10 @@ func (c *Client) SendPhotoWithKeyBoard(ctx context.Context, chatID int, photoPat
 		return fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.host,
-		Path:   path.Join(c.basePath, "sendPhoto"),
-	}
+	ctx, span := c.startSpan(ctx, sendPhotoMethod)
+	defer func() { c.observe(span, sendPhotoMethod, err) }()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), body)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.methodURL(sendPhotoMethod), body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -174,20 +188,17 @@ func (c *Client) SendPhotoWithKeyBoard(ctx context.Context, chatID int, photoPat
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram API error: %s", string(body))
+		return apiError(sendPhotoMethod, resp.StatusCode, body)
 	}
 
 	return nil
 }
 
 func (c *Client) doRequest(ctx context.Context, method string, query url.Values) (data []byte, err error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.host,
-		Path:   path.Join(c.basePath, method),
-	}
+	ctx, span := c.startSpan(ctx, method)
+	defer func() { c.observe(span, method, err) }()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.methodURL(method), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request:%w", err)
 	}
@@ -207,5 +218,64 @@ func (c *Client) doRequest(ctx context.Context, method string, query url.Values)
 		return nil, fmt.Errorf("failed to read response:%w", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(method, resp.StatusCode, data)
+	}
+
 	return data, nil
 }
+
+func (c *Client) doJSONRequest(ctx context.Context, method string, body any) (err error) {
+	ctx, span := c.startSpan(ctx, method)
+	defer func() { c.observe(span, method, err) }()
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("can't marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.methodURL(method), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("can't create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("can't do request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return apiError(method, resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+func (c *Client) methodURL(method string) string {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   path.Join(c.basePath, method),
+	}
+
+	return u.String()
+}
+
+func (c *Client) startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "telegram."+method, attribute.String("telegram.method", method))
+}
+
+func (c *Client) observe(span trace.Span, method string, err error) {
+	metrics.TelegramRequests.WithLabelValues(method).Inc()
+	if err != nil {
+		metrics.TelegramErrors.WithLabelValues(method).Inc()
+	}
+
+	tracing.End(span, err)
+}
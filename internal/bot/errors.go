@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError — the Bot API answered with ok=false, Code is its error_code
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	RetryAfter  time.Duration // set on 429, how long to wait before the next request
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error: %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// IsBlocked — the user blocked the bot or deleted the account, sending to them again is pointless
+func IsBlocked(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
+
+// RetryAfter — the wait telegram asked for after flooding, false when it did not ask
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests {
+		return apiErr.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// apiError — error of the non 200 response, body is the Bot API error json when it can be parsed
+func apiError(method string, status int, body []byte) error {
+	var res struct {
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+
+	if err := json.Unmarshal(body, &res); err != nil || res.ErrorCode == 0 {
+		res.ErrorCode = status
+		res.Description = strings.TrimSpace(string(body))
+	}
+
+	return &APIError{
+		Method:      method,
+		Code:        res.ErrorCode,
+		Description: res.Description,
+		RetryAfter:  time.Duration(res.Parameters.RetryAfter) * time.Second,
+	}
+}
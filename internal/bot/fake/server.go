@@ -0,0 +1,305 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"drillCore/internal/bot"
+)
+
+// maxPoll — getUpdates never holds a request longer than this, whatever timeout the client asks for
+const maxPoll = 2 * time.Second
+
+// Message — what the bot sent through the fake api
+type Message struct {
+	ChatID    int
+	Text      string
+	ParseMode bot.ParseMode
+	Markup    bot.ReplyMarkup
+	Photo     bool
+}
+
+// Server — fake Telegram Bot API: updates are injected with SendText and Press,
+// everything the bot sends is published to Messages, which must be drained
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	updates  []bot.Update
+	notify   chan struct{}
+	commands []bot.BotCommand
+	blocked  map[int]struct{}
+	failures map[int]failure
+
+	messages chan Message
+}
+
+func New() *Server {
+	s := &Server{
+		notify:   make(chan struct{}),
+		blocked:  make(map[int]struct{}),
+		failures: make(map[int]failure),
+		messages: make(chan Message, 100),
+	}
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// URL — value for config.TelegramEnvs.BaseUrl
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+func (s *Server) Messages() <-chan Message {
+	return s.messages
+}
+
+// Commands — the last list registered with setMyCommands
+func (s *Server) Commands() []bot.BotCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.commands
+}
+
+// Block — the user blocks the bot, messages to their chat fail with 403 from now on
+func (s *Server) Block(userID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blocked[userID] = struct{}{}
+}
+
+// failure — status of the next sends to a chat
+type failure struct {
+	status int
+	left   int
+}
+
+// Fail — the next n messages to the chat of the user fail with status,
+// 429 asks to retry right away
+func (s *Server) Fail(userID, status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[userID] = failure{status: status, left: n}
+}
+
+// failure — the status the next send to the chat fails with, 0 when it goes through
+func (s *Server) failure(chatID int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.failures[chatID]
+	if !ok {
+		return 0
+	}
+
+	if f.left--; f.left <= 0 {
+		delete(s.failures, chatID)
+	} else {
+		s.failures[chatID] = f
+	}
+
+	return f.status
+}
+
+// SendText — the user writes text to the bot in the private chat
+func (s *Server) SendText(userID int, text string) {
+	s.push(bot.Update{Message: &bot.IncomingMessage{
+		Text: text,
+		From: sender(userID),
+		Chat: bot.Chat{ID: userID},
+	}})
+}
+
+// Press — the user presses an inline button with the callback data
+func (s *Server) Press(userID int, data string) {
+	s.push(bot.Update{CallbackQuery: &bot.CallbackQuery{
+		From:    sender(userID),
+		Message: bot.IncomingMessage{Chat: bot.Chat{ID: userID}},
+		Data:    data,
+	}})
+}
+
+// sender — every fake user is an english-speaking pilot named after their ID
+func sender(userID int) bot.From {
+	return bot.From{ID: userID, FirstName: "Pilot " + strconv.Itoa(userID), LanguageCode: "en"}
+}
+
+func (s *Server) push(u bot.Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	u.ID = s.nextID
+	if u.CallbackQuery != nil {
+		u.CallbackQuery.ID = strconv.Itoa(u.ID)
+	}
+
+	s.updates = append(s.updates, u)
+
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	// /bot<token>/<method>
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	switch method {
+	case "getUpdates":
+		s.getUpdates(w, r)
+
+	case "sendMessage":
+		msg, err := parseMessage(r)
+		if err != nil {
+			reply(w, http.StatusBadRequest, false, err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		_, blocked := s.blocked[msg.ChatID]
+		s.mu.Unlock()
+
+		if blocked {
+			reply(w, http.StatusForbidden, false, "Forbidden: bot was blocked by the user")
+			return
+		}
+
+		if status := s.failure(msg.ChatID); status != 0 {
+			reply(w, status, false, http.StatusText(status))
+			return
+		}
+
+		s.messages <- msg
+		reply(w, http.StatusOK, true, true)
+
+	case "sendPhoto":
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			reply(w, http.StatusBadRequest, false, err.Error())
+			return
+		}
+
+		msg := Message{Photo: true, Text: r.FormValue("caption"), ParseMode: bot.ParseMode(r.FormValue("parse_mode"))}
+		msg.ChatID, _ = strconv.Atoi(r.FormValue("chat_id"))
+		_ = json.Unmarshal([]byte(r.FormValue("reply_markup")), &msg.Markup)
+
+		s.messages <- msg
+		reply(w, http.StatusOK, true, true)
+
+	case "setMyCommands":
+		var req struct {
+			Commands []bot.BotCommand `json:"commands"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			reply(w, http.StatusBadRequest, false, err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = req.Commands
+		s.mu.Unlock()
+
+		reply(w, http.StatusOK, true, true)
+
+	default:
+		reply(w, http.StatusNotFound, false, "method not found")
+	}
+}
+
+func (s *Server) getUpdates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	timeout, _ := strconv.Atoi(q.Get("timeout"))
+
+	deadline := time.After(min(time.Duration(timeout)*time.Second, maxPoll))
+	for {
+		s.mu.Lock()
+		// updates below the offset are confirmed, same as in the real api
+		s.updates = slices.DeleteFunc(s.updates, func(u bot.Update) bool { return u.ID < offset })
+
+		res := make([]bot.Update, 0)
+		for _, u := range s.updates {
+			if u.ID >= offset && (limit <= 0 || len(res) < limit) {
+				res = append(res, u)
+			}
+		}
+		notify := s.notify
+		s.mu.Unlock()
+
+		if len(res) > 0 {
+			reply(w, http.StatusOK, true, res)
+			return
+		}
+
+		select {
+		case <-notify:
+		case <-deadline:
+			reply(w, http.StatusOK, true, res)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// parseMessage — sendMessage comes either as a query (SendMessage) or as json (SendMessageWithKeyboard)
+func parseMessage(r *http.Request) (Message, error) {
+	if r.Method == http.MethodGet {
+		return queryMessage(r.URL.Query()), nil
+	}
+
+	var req struct {
+		ChatID      int             `json:"chat_id"`
+		Text        string          `json:"text"`
+		ParseMode   bot.ParseMode   `json:"parse_mode"`
+		ReplyMarkup bot.ReplyMarkup `json:"reply_markup"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Message{}, err
+	}
+
+	return Message{ChatID: req.ChatID, Text: req.Text, ParseMode: req.ParseMode, Markup: req.ReplyMarkup}, nil
+}
+
+func queryMessage(q url.Values) Message {
+	msg := Message{Text: q.Get("text"), ParseMode: bot.ParseMode(q.Get("parse_mode"))}
+	msg.ChatID, _ = strconv.Atoi(q.Get("chat_id"))
+
+	return msg
+}
+
+func reply(w http.ResponseWriter, status int, ok bool, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	body := map[string]any{"ok": ok}
+	if ok {
+		body["result"] = result
+	} else {
+		body["error_code"] = status
+		body["description"] = result
+	}
+
+	if status == http.StatusTooManyRequests {
+		body["parameters"] = map[string]int{"retry_after": 0}
+	}
+
+	_ = json.NewEncoder(w).Encode(body)
+}
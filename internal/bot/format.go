@@ -0,0 +1,43 @@
+package bot
+
+import "strings"
+
+var (
+	htmlReplacer = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+
+	markdownV2Replacer = strings.NewReplacer(
+		`\`, `\\`,
+		"_", `\_`,
+		"*", `\*`,
+		"[", `\[`,
+		"]", `\]`,
+		"(", `\(`,
+		")", `\)`,
+		"~", `\~`,
+		"`", "\\`",
+		">", `\>`,
+		"#", `\#`,
+		"+", `\+`,
+		"-", `\-`,
+		"=", `\=`,
+		"|", `\|`,
+		"{", `\{`,
+		"}", `\}`,
+		".", `\.`,
+		"!", `\!`,
+	)
+)
+
+// EscapeHTML — escapes user input before it is placed into a ParseModeHTML message
+func EscapeHTML(s string) string {
+	return htmlReplacer.Replace(s)
+}
+
+// EscapeMarkdownV2 — escapes user input before it is placed into a ParseModeMarkdownV2 message
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Replacer.Replace(s)
+}
@@ -0,0 +1,50 @@
+package bot
+
+import "testing"
+
+func TestEscapeHTML(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"giga drill", "giga drill"},
+		{"<b>drill</b>", "&lt;b&gt;drill&lt;/b&gt;"},
+		{"Simon & Kamina", "Simon &amp; Kamina"},
+		{"&lt;", "&amp;lt;"},
+		{"бур <5>", "бур &lt;5&gt;"},
+	}
+
+	for _, tc := range cases {
+		if got := EscapeHTML(tc.in); got != tc.want {
+			t.Errorf("EscapeHTML(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	// every character reserved by MarkdownV2 gets a backslash
+	for _, c := range "_*[]()~`>#+-=|{}.!\\" {
+		in := "a" + string(c) + "b"
+		want := `a\` + string(c) + "b"
+
+		if got := EscapeMarkdownV2(in); got != want {
+			t.Errorf("EscapeMarkdownV2(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	cases := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"giga drill", "giga drill"},
+		{"1.500₽ (paid!)", `1\.500₽ \(paid\!\)`},
+		{`\*`, `\\\*`},
+		{"<&>", `<&\>`},
+	}
+
+	for _, tc := range cases {
+		if got := EscapeMarkdownV2(tc.in); got != tc.want {
+			t.Errorf("EscapeMarkdownV2(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -5,3 +5,28 @@ func NewInlineKeyboard(buttons [][]InlineKeyboardButton) ReplyMarkup {
 		InlineKeyboard: buttons,
 	}
 }
+
+// NewReplyKeyboard — persistent keyboard below the input field, every button sends its text as a message
+func NewReplyKeyboard(buttons [][]KeyboardButton, placeholder string) ReplyMarkup {
+	return ReplyMarkup{
+		Keyboard:              buttons,
+		IsPersistent:          true,
+		ResizeKeyboard:        true,
+		InputFieldPlaceholder: placeholder,
+	}
+}
+
+// NewRemoveKeyboard — hides the current reply keyboard
+func NewRemoveKeyboard() ReplyMarkup {
+	return ReplyMarkup{
+		RemoveKeyboard: true,
+	}
+}
+
+// NewForceReply — opens the reply interface, as if the user selected the bot's message and tapped "Reply"
+func NewForceReply(placeholder string) ReplyMarkup {
+	return ReplyMarkup{
+		ForceReply:            true,
+		InputFieldPlaceholder: placeholder,
+	}
+}
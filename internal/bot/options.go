@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+)
+
+type ParseMode string
+
+const (
+	ParseModeNone       ParseMode = ""
+	ParseModeHTML       ParseMode = "HTML"
+	ParseModeMarkdownV2 ParseMode = "MarkdownV2"
+)
+
+type MessageOptions struct {
+	ParseMode           ParseMode
+	DisableLinkPreview  bool
+	DisableNotification bool
+}
+
+type MessageOption func(o *MessageOptions)
+
+// WithParseMode — telegram parses entities in the text (caption) with the given mode
+func WithParseMode(mode ParseMode) MessageOption {
+	return func(o *MessageOptions) {
+		o.ParseMode = mode
+	}
+}
+
+// WithHTML — shortcut for WithParseMode(ParseModeHTML)
+func WithHTML() MessageOption {
+	return WithParseMode(ParseModeHTML)
+}
+
+// WithoutLinkPreview — disables link previews for links in the message
+func WithoutLinkPreview() MessageOption {
+	return func(o *MessageOptions) {
+		o.DisableLinkPreview = true
+	}
+}
+
+// Silent — users will receive a notification with no sound
+func Silent() MessageOption {
+	return func(o *MessageOptions) {
+		o.DisableNotification = true
+	}
+}
+
+func newMessageOptions(opts ...MessageOption) MessageOptions {
+	var o MessageOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+type linkPreviewOptions struct {
+	IsDisabled bool `json:"is_disabled"`
+}
+
+func (o MessageOptions) linkPreview() *linkPreviewOptions {
+	if !o.DisableLinkPreview {
+		return nil
+	}
+
+	return &linkPreviewOptions{IsDisabled: true}
+}
+
+func (o MessageOptions) applyQuery(q url.Values) {
+	if o.ParseMode != ParseModeNone {
+		q.Add("parse_mode", string(o.ParseMode))
+	}
+
+	if lp := o.linkPreview(); lp != nil {
+		data, _ := json.Marshal(lp)
+		q.Add("link_preview_options", string(data))
+	}
+
+	if o.DisableNotification {
+		q.Add("disable_notification", strconv.FormatBool(true))
+	}
+}
+
+func (o MessageOptions) applyForm(w *multipart.Writer) {
+	if o.ParseMode != ParseModeNone {
+		_ = w.WriteField("parse_mode", string(o.ParseMode))
+	}
+
+	if o.DisableNotification {
+		_ = w.WriteField("disable_notification", strconv.FormatBool(true))
+	}
+}
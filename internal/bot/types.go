@@ -18,8 +18,11 @@ type IncomingMessage struct {
 }
 
 type From struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
+	ID           int    `json:"id"`
+	Username     string `json:"username"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	LanguageCode string `json:"language_code"` // IETF tag of the user's telegram client, may be empty
 }
 
 type Chat struct {
@@ -34,10 +37,32 @@ type CallbackQuery struct {
 }
 
 type ReplyMarkup struct {
-	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard,omitempty"`
+
+	Keyboard              [][]KeyboardButton `json:"keyboard,omitempty"`
+	IsPersistent          bool               `json:"is_persistent,omitempty"`
+	ResizeKeyboard        bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard       bool               `json:"one_time_keyboard,omitempty"`
+	InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"`
+
+	RemoveKeyboard bool `json:"remove_keyboard,omitempty"`
+	ForceReply     bool `json:"force_reply,omitempty"`
+}
+
+func (m ReplyMarkup) IsEmpty() bool {
+	return m.InlineKeyboard == nil && m.Keyboard == nil && !m.RemoveKeyboard && !m.ForceReply
 }
 
 type InlineKeyboardButton struct {
 	Text         string `json:"text"`
 	CallbackData string `json:"callback_data"`
 }
+
+type KeyboardButton struct {
+	Text string `json:"text"`
+}
+
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,220 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// Storage — broadcasts and their deliveries, see internal/storage/user
+type Storage interface {
+	CreateBroadcast(ctx context.Context, b *model.Broadcast) (*model.Broadcast, error)
+	Broadcasts(ctx context.Context, limit int) ([]*model.Broadcast, error)
+	RunningBroadcasts(ctx context.Context) ([]*model.Broadcast, error)
+	PendingDeliveries(ctx context.Context, broadcastID int64, limit int) ([]*model.Delivery, error)
+	SaveDelivery(ctx context.Context, d *model.Delivery) error
+	FinishBroadcast(ctx context.Context, id int64) (*model.Broadcast, error)
+}
+
+// Authorizer — access control, users who lost access to the bot are skipped
+type Authorizer interface {
+	Check(userID int) error
+}
+
+const (
+	// maxAttempts — sends of one delivery before it is failed, flood waits are not counted
+	maxAttempts = 3
+
+	// idlePoll — how often broadcasts created while the job slept are looked up
+	idlePoll = 30 * time.Second
+)
+
+// Job — delivers the broadcasts in the background. Every delivery is persisted, so a restarted
+// job continues from the pending ones; a message sent right before a crash may be sent twice.
+type Job struct {
+	tg        *bot.Client
+	storage   Storage
+	access    Authorizer
+	interval  time.Duration // pause between two messages
+	batchSize int
+	wake      chan struct{}
+	logger    *zap.SugaredLogger
+}
+
+// New — rate is messages per second, telegram allows about 30 for a bot
+func New(tg *bot.Client, storage Storage, access Authorizer, rate, batchSize int, logger *zap.SugaredLogger) *Job {
+	return &Job{
+		tg:        tg,
+		storage:   storage,
+		access:    access,
+		interval:  time.Second / time.Duration(rate),
+		batchSize: batchSize,
+		wake:      make(chan struct{}, 1),
+		logger:    logger,
+	}
+}
+
+// Broadcast — queues the text for every user who did not block the bot, the admin in chatID
+// gets the report when it is delivered
+func (j *Job) Broadcast(ctx context.Context, text string, adminID, chatID int) (*model.Broadcast, error) {
+	b, err := j.storage.CreateBroadcast(ctx, &model.Broadcast{
+		Text:      text,
+		CreatedBy: int64(adminID),
+		ChatID:    int64(chatID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create broadcast: %w", err)
+	}
+
+	j.logger.Infow("broadcast queued", "broadcast", b.ID, "admin", adminID, "recipients", b.Total)
+
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+
+	return b, nil
+}
+
+// Broadcasts — the latest broadcasts with their counters
+func (j *Job) Broadcasts(ctx context.Context, limit int) ([]*model.Broadcast, error) {
+	return j.storage.Broadcasts(ctx, limit)
+}
+
+// Start — delivers the running broadcasts until ctx is done
+func (j *Job) Start(ctx context.Context) error {
+	for {
+		if err := j.deliverAll(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Errorw("failed to deliver broadcasts", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-j.wake:
+		case <-time.After(idlePoll):
+		}
+	}
+}
+
+func (j *Job) deliverAll(ctx context.Context) error {
+	running, err := j.storage.RunningBroadcasts(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range running {
+		if err := j.deliver(ctx, b); err != nil {
+			return fmt.Errorf("failed to deliver broadcast %d: %w", b.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// deliver — sends the pending deliveries batch by batch, one message per interval
+func (j *Job) deliver(ctx context.Context, b *model.Broadcast) error {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		batch, err := j.storage.PendingDeliveries(ctx, b.ID, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(batch) == 0 {
+			return j.finish(ctx, b.ID)
+		}
+
+		for _, d := range batch {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+
+			if err := j.send(ctx, b, d); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (j *Job) send(ctx context.Context, b *model.Broadcast, d *model.Delivery) error {
+	if err := j.access.Check(int(d.UserID)); err != nil {
+		d.Status, d.Error = model.DeliverySkipped, err.Error()
+		metrics.BroadcastDeliveries.WithLabelValues(string(d.Status)).Inc()
+
+		return j.storage.SaveDelivery(ctx, d)
+	}
+
+	err := j.tg.SendMessage(ctx, int(d.ChatID), b.Text)
+
+	// flood control, the delivery stays pending and the whole job waits
+	if wait, ok := bot.RetryAfter(err); ok {
+		j.logger.Warnw("broadcast throttled by telegram", "broadcast", b.ID, "retry_after", wait)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+			return nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	d.Attempts++
+
+	switch {
+	case err == nil:
+		d.Status, d.Error = model.DeliverySent, ""
+	case bot.IsBlocked(err):
+		d.Status, d.Error = model.DeliveryBlocked, err.Error()
+	case d.Attempts >= maxAttempts:
+		d.Status, d.Error = model.DeliveryFailed, err.Error()
+	default:
+		d.Error = err.Error()
+	}
+
+	if err != nil {
+		j.logger.Warnw("failed to deliver broadcast", "broadcast", b.ID, "user", d.UserID, "status", d.Status, "error", err)
+	}
+
+	metrics.BroadcastDeliveries.WithLabelValues(string(d.Status)).Inc()
+
+	return j.storage.SaveDelivery(ctx, d)
+}
+
+// finish — closes the broadcast and reports the counters to the admin who started it
+func (j *Job) finish(ctx context.Context, id int64) error {
+	b, err := j.storage.FinishBroadcast(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	j.logger.Infow("broadcast finished",
+		"broadcast", b.ID,
+		"sent", b.Sent,
+		"failed", b.Failed,
+		"blocked", b.Blocked,
+		"skipped", b.Skipped,
+	)
+
+	report := fmt.Sprintf(manager.MsgBroadcastDoneFormat, b.ID, b.Sent, b.Failed, b.Blocked, b.Skipped)
+	if err := j.tg.SendMessage(ctx, int(b.ChatID), report); err != nil {
+		j.logger.Errorw("failed to report broadcast", "broadcast", b.ID, "admin_chat", b.ChatID, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package broadcast_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"drillCore/internal/access"
+	"drillCore/internal/bot"
+	"drillCore/internal/bot/fake"
+	"drillCore/internal/broadcast"
+	"drillCore/internal/config"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/model"
+	"drillCore/internal/storage/user/memory"
+
+	"go.uber.org/zap"
+)
+
+const (
+	adminChat = 100
+	bannedID  = 5
+)
+
+type guard struct{}
+
+func (guard) Check(userID int) error {
+	if userID == bannedID {
+		return access.ErrBanned
+	}
+
+	return nil
+}
+
+// recorder — memory storage that keeps every saved delivery
+type recorder struct {
+	*memory.UserStorage
+
+	mu    sync.Mutex
+	saved map[int64][]model.Delivery // user ID -> saves in order
+}
+
+func (r *recorder) SaveDelivery(ctx context.Context, d *model.Delivery) error {
+	r.mu.Lock()
+	r.saved[d.UserID] = append(r.saved[d.UserID], *d)
+	r.mu.Unlock()
+
+	return r.UserStorage.SaveDelivery(ctx, d)
+}
+
+type env struct {
+	t       *testing.T
+	srv     *fake.Server
+	tg      *bot.Client
+	storage *recorder
+}
+
+// newEnv — users with the given IDs, each in the private chat of the same ID
+func newEnv(t *testing.T, userIDs ...int64) *env {
+	t.Helper()
+
+	srv := fake.New()
+	t.Cleanup(srv.Close)
+
+	storage := &recorder{UserStorage: memory.New(), saved: make(map[int64][]model.Delivery)}
+	for _, id := range userIDs {
+		if err := storage.SaveUser(t.Context(), &model.User{ID: id, ChatID: id}); err != nil {
+			t.Fatalf("save user: %v", err)
+		}
+	}
+
+	tg := bot.New(&config.TelegramEnvs{Token: "test", BaseUrl: srv.URL()}, zap.NewNop().Sugar())
+
+	return &env{t: t, srv: srv, tg: tg, storage: storage}
+}
+
+// run — starts a job, as after a restart, and waits for the report of the broadcast; returns the messages
+// the users got on the way
+func (env *env) run(id int64) map[int]int {
+	env.t.Helper()
+
+	job := broadcast.New(env.tg, env.storage, guard{}, 1000, 2, zap.NewNop().Sugar())
+
+	ctx, cancel := context.WithCancel(env.t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = job.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	got := make(map[int]int)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case m := <-env.srv.Messages():
+			if m.ChatID != adminChat {
+				got[m.ChatID]++
+				continue
+			}
+
+			b, err := env.storage.Broadcast(env.t.Context(), id)
+			if err != nil {
+				env.t.Fatalf("broadcast: %v", err)
+			}
+
+			want := fmt.Sprintf(manager.MsgBroadcastDoneFormat, b.ID, b.Sent, b.Failed, b.Blocked, b.Skipped)
+			if m.Text != want {
+				env.t.Fatalf("report %q, want %q", m.Text, want)
+			}
+
+			return got
+
+		case <-timeout:
+			env.t.Fatalf("broadcast %d is not reported, users got %v", id, got)
+		}
+	}
+}
+
+func (env *env) create() *model.Broadcast {
+	env.t.Helper()
+
+	b, err := env.storage.CreateBroadcast(env.t.Context(), &model.Broadcast{Text: "spin on", CreatedBy: 1, ChatID: adminChat})
+	if err != nil {
+		env.t.Fatalf("create broadcast: %v", err)
+	}
+
+	return b
+}
+
+func TestJobResumesPendingDeliveries(t *testing.T) {
+	env := newEnv(t, 1, 2, 3)
+	b := env.create()
+
+	// the job before the restart got to the first user
+	pending, err := env.storage.PendingDeliveries(t.Context(), b.ID, 10)
+	if err != nil {
+		t.Fatalf("pending deliveries: %v", err)
+	}
+	first := pending[0]
+	first.Status, first.Attempts = model.DeliverySent, 1
+	if err := env.storage.UserStorage.SaveDelivery(t.Context(), first); err != nil {
+		t.Fatalf("save delivery: %v", err)
+	}
+
+	got := env.run(b.ID)
+
+	if _, ok := got[int(first.UserID)]; ok || len(got) != 2 {
+		t.Fatalf("users got %v, want only the pending ones", got)
+	}
+	for id, n := range got {
+		if n != 1 {
+			t.Fatalf("user %d got %d messages, want 1", id, n)
+		}
+	}
+
+	done, err := env.storage.Broadcast(t.Context(), b.ID)
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if done.Status != model.BroadcastDone || done.Sent != 3 || done.Pending != 0 {
+		t.Fatalf("got broadcast %+v, want done with 3 sent", done)
+	}
+
+	// nothing is left for the next restart
+	if got := env.run(env.create().ID); len(got) != 3 {
+		t.Fatalf("the next broadcast reached %v, want every user once", got)
+	}
+}
+
+func TestJobDeliveryStatuses(t *testing.T) {
+	const (
+		okID      = 1
+		blockedID = 2
+		failingID = 3
+		floodID   = 4
+	)
+
+	env := newEnv(t, okID, blockedID, failingID, floodID, bannedID)
+	env.srv.Block(blockedID)
+	env.srv.Fail(failingID, http.StatusBadRequest, 10)
+	env.srv.Fail(floodID, http.StatusTooManyRequests, 1)
+
+	b := env.create()
+	got := env.run(b.ID)
+
+	if got[okID] != 1 || got[floodID] != 1 || len(got) != 2 {
+		t.Fatalf("users got %v, want one message to %d and %d", got, okID, floodID)
+	}
+
+	done, err := env.storage.Broadcast(t.Context(), b.ID)
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if done.Sent != 2 || done.Blocked != 1 || done.Failed != 1 || done.Skipped != 1 || done.Pending != 0 {
+		t.Fatalf("got counters %+v, want 2 sent, 1 blocked, 1 failed, 1 skipped", done)
+	}
+
+	saved := env.storage.saved
+	cases := []struct {
+		name   string
+		userID int64
+		want   []model.DeliveryStatus
+	}{
+		{"sent", okID, []model.DeliveryStatus{model.DeliverySent}},
+		{"blocked on the first attempt", blockedID, []model.DeliveryStatus{model.DeliveryBlocked}},
+		{"failed after every attempt", failingID, []model.DeliveryStatus{model.DeliveryPending, model.DeliveryPending, model.DeliveryFailed}},
+		// the flood wait is not saved, the delivery stays pending and is sent on the same attempt
+		{"flood wait", floodID, []model.DeliveryStatus{model.DeliverySent}},
+		{"skipped without access", bannedID, []model.DeliveryStatus{model.DeliverySkipped}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deliveries := saved[tc.userID]
+			if len(deliveries) != len(tc.want) {
+				t.Fatalf("saved %+v, want statuses %v", deliveries, tc.want)
+			}
+
+			for i, d := range deliveries {
+				if d.Status != tc.want[i] {
+					t.Fatalf("save %d has status %s, want %s", i+1, d.Status, tc.want[i])
+				}
+				if d.Status != model.DeliverySkipped && d.Attempts != i+1 {
+					t.Fatalf("save %d counts %d attempts, want %d", i+1, d.Attempts, i+1)
+				}
+			}
+		})
+	}
+
+	u, err := env.storage.User(t.Context(), blockedID)
+	if err != nil {
+		t.Fatalf("user: %v", err)
+	}
+	if !u.Blocked {
+		t.Fatal("user who blocked the bot is not marked blocked")
+	}
+}
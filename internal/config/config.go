@@ -5,23 +5,60 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
 	env   = "APP_ENV"
 	debug = "APP_DEBUG"
 
+	adminIDs   = "APP_ADMIN_IDS"
+	allowlist  = "APP_ALLOWLIST"
+	allowedIDs = "APP_ALLOWED_IDS"
+	undoWindow = "APP_UNDO_WINDOW"
+	httpAddr   = "APP_HTTP_ADDR"
+
+	readyPollWindow  = "APP_READY_POLL_WINDOW"
+	readyStallWindow = "APP_READY_STALL_WINDOW"
+
+	rateLimit  = "APP_RATE_LIMIT"
+	rateWindow = "APP_RATE_WINDOW"
+
+	retryAttempts = "APP_RETRY_ATTEMPTS"
+	retryBackoff  = "APP_RETRY_BACKOFF"
+
+	broadcastRate  = "APP_BROADCAST_RATE"
+	broadcastBatch = "APP_BROADCAST_BATCH"
+
+	traceExporter    = "TRACE_EXPORTER"
+	traceServiceName = "TRACE_SERVICE_NAME"
+
+	dbDriver = "DB_DRIVER"
+	dbPath   = "DB_PATH"
+
 	dbHost     = "DB_HOST"
 	dbPort     = "DB_PORT"
 	dbUser     = "DB_USER"
 	dbPassword = "DB_PASS"
 	dbName     = "DB_NAME"
 
+	dbMaxConns          = "DB_MAX_CONNS"
+	dbMinConns          = "DB_MIN_CONNS"
+	dbStatementTimeout  = "DB_STATEMENT_TIMEOUT"
+	dbHealthCheckPeriod = "DB_HEALTH_CHECK_PERIOD"
+	dbAutoMigrate       = "DB_AUTO_MIGRATE"
+
 	tgToken     = "TG_TOKEN"
 	tgBaseURL   = "TG_BASE_URL"
 	tgBatchSize = "TG_BATCH_SIZE"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 var (
 	ErrEnvNotExists  = errors.New("environment variable not exists")
 	ErrEnvNotCorrect = errors.New("invalid environment variable")
@@ -36,14 +73,45 @@ type ServiceConfig struct {
 type AppEnvs struct {
 	Env       string
 	DebugFlag bool
+
+	AdminIDs   []int
+	Allowlist  bool  // private deployment, only admins and allowed users may use the bot
+	AllowedIDs []int // allowed besides the ones added with /allow
+	UndoWindow time.Duration
+	HTTPAddr   string // metrics and health endpoints
+
+	ReadyPollWindow  time.Duration // max age of the last successful getUpdates
+	ReadyStallWindow time.Duration // max time the consumer loop may make no progress
+
+	RateLimit  int // events per user in RateWindow, 0 disables the flood protection
+	RateWindow time.Duration
+
+	RetryAttempts int           // processing attempts before an update goes to the dead letters
+	RetryBackoff  time.Duration // pause after the first failed attempt, doubles after each next one
+
+	BroadcastRate  int // broadcast messages per second, telegram allows about 30
+	BroadcastBatch int // deliveries loaded from the storage at once
+
+	TraceExporter string // none/stdout/otlp
+	ServiceName   string
 }
 
 type DbEnvs struct {
+	Driver string
+	Path   string // sqlite database file
+
 	Host string
 	Port string
 	User string
 	Pass string
 	Name string
+
+	MaxConns          int
+	MinConns          int
+	StatementTimeout  time.Duration
+	HealthCheckPeriod time.Duration
+
+	AutoMigrate bool
 }
 
 type TelegramEnvs struct {
@@ -75,6 +143,11 @@ func New() (*ServiceConfig, error) {
 	}, nil
 }
 
+// NewDB — only the database part of the config, enough for `app migrate`
+func NewDB() (*DbEnvs, error) {
+	return dbEnvsEnvs()
+}
+
 func appEnvs() (*AppEnvs, error) {
 	debugStr, ok := os.LookupEnv(debug)
 	if !ok {
@@ -91,36 +164,170 @@ func appEnvs() (*AppEnvs, error) {
 		return nil, fmt.Errorf("%w: %s", ErrEnvNotExists, env)
 	}
 
-	return &AppEnvs{DebugFlag: df, Env: e}, nil
+	admins, err := optionalIntList(adminIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	private, err := optionalBool(allowlist, false)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := optionalIntList(allowedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	undo, err := optionalDuration(undoWindow, 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	pollWindow, err := optionalDuration(readyPollWindow, 90*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	stallWindow, err := optionalDuration(readyStallWindow, 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := optionalInt(rateLimit, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := optionalDuration(rateWindow, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := optionalInt(retryAttempts, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff, err := optionalDuration(retryBackoff, 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+
+	bRate, err := optionalInt(broadcastRate, 25)
+	if err != nil {
+		return nil, err
+	}
+
+	if bRate <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotCorrect, broadcastRate)
+	}
+
+	bBatch, err := optionalInt(broadcastBatch, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	if bBatch <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotCorrect, broadcastBatch)
+	}
+
+	return &AppEnvs{
+		DebugFlag:        df,
+		Env:              e,
+		AdminIDs:         admins,
+		Allowlist:        private,
+		AllowedIDs:       allowed,
+		UndoWindow:       undo,
+		HTTPAddr:         optionalString(httpAddr, ":8080"),
+		ReadyPollWindow:  pollWindow,
+		ReadyStallWindow: stallWindow,
+		RateLimit:        limit,
+		RateWindow:       window,
+		RetryAttempts:    attempts,
+		RetryBackoff:     backoff,
+		BroadcastRate:    bRate,
+		BroadcastBatch:   bBatch,
+		TraceExporter:    optionalString(traceExporter, "none"),
+		ServiceName:      optionalString(traceServiceName, "drillcore"),
+	}, nil
 }
 
 func dbEnvsEnvs() (*DbEnvs, error) {
-	host, ok := os.LookupEnv(dbHost)
-	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrEnvNotExists, dbHost)
+	res := &DbEnvs{Driver: DriverPostgres, Path: "drillcore.db"}
+
+	if v, ok := os.LookupEnv(dbDriver); ok && v != "" {
+		res.Driver = v
 	}
 
-	port, ok := os.LookupEnv(dbPort)
-	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrEnvNotExists, dbPort)
+	switch res.Driver {
+	case DriverPostgres:
+		if err := pgEnvs(res); err != nil {
+			return nil, err
+		}
+
+	case DriverSQLite:
+		if v, ok := os.LookupEnv(dbPath); ok && v != "" {
+			res.Path = v
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotCorrect, dbDriver)
 	}
 
-	user, ok := os.LookupEnv(dbUser)
-	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrEnvNotExists, dbUser)
+	var err error
+	res.MaxConns, err = optionalInt(dbMaxConns, 10)
+	if err != nil {
+		return nil, err
 	}
 
-	pass, ok := os.LookupEnv(dbPassword)
-	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrEnvNotExists, dbPassword)
+	res.MinConns, err = optionalInt(dbMinConns, 0)
+	if err != nil {
+		return nil, err
 	}
 
-	name, ok := os.LookupEnv(dbName)
-	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrEnvNotExists, dbName)
+	res.StatementTimeout, err = optionalDuration(dbStatementTimeout, 5*time.Second)
+	if err != nil {
+		return nil, err
 	}
 
-	return &DbEnvs{Port: port, Host: host, User: user, Pass: pass, Name: name}, nil
+	res.HealthCheckPeriod, err = optionalDuration(dbHealthCheckPeriod, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	res.AutoMigrate, err = optionalBool(dbAutoMigrate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func pgEnvs(res *DbEnvs) error {
+	var ok bool
+
+	if res.Host, ok = os.LookupEnv(dbHost); !ok {
+		return fmt.Errorf("%w: %s", ErrEnvNotExists, dbHost)
+	}
+
+	if res.Port, ok = os.LookupEnv(dbPort); !ok {
+		return fmt.Errorf("%w: %s", ErrEnvNotExists, dbPort)
+	}
+
+	if res.User, ok = os.LookupEnv(dbUser); !ok {
+		return fmt.Errorf("%w: %s", ErrEnvNotExists, dbUser)
+	}
+
+	if res.Pass, ok = os.LookupEnv(dbPassword); !ok {
+		return fmt.Errorf("%w: %s", ErrEnvNotExists, dbPassword)
+	}
+
+	if res.Name, ok = os.LookupEnv(dbName); !ok {
+		return fmt.Errorf("%w: %s", ErrEnvNotExists, dbName)
+	}
+
+	return nil
 }
 
 func tgEnvs() (*TelegramEnvs, error) {
@@ -146,3 +353,75 @@ func tgEnvs() (*TelegramEnvs, error) {
 
 	return &TelegramEnvs{Token: token, BaseUrl: bUrl, BatchSize: bSize}, nil
 }
+
+func optionalString(key string, def string) string {
+	str, ok := os.LookupEnv(key)
+	if !ok || str == "" {
+		return def
+	}
+
+	return str
+}
+
+func optionalInt(key string, def int) (int, error) {
+	str, ok := os.LookupEnv(key)
+	if !ok || str == "" {
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrEnvNotCorrect, key)
+	}
+
+	return v, nil
+}
+
+func optionalDuration(key string, def time.Duration) (time.Duration, error) {
+	str, ok := os.LookupEnv(key)
+	if !ok || str == "" {
+		return def, nil
+	}
+
+	v, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrEnvNotCorrect, key)
+	}
+
+	return v, nil
+}
+
+func optionalBool(key string, def bool) (bool, error) {
+	str, ok := os.LookupEnv(key)
+	if !ok || str == "" {
+		return def, nil
+	}
+
+	v, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrEnvNotCorrect, key)
+	}
+
+	return v, nil
+}
+
+// optionalIntList — comma separated list of ints, empty when the variable is not set
+func optionalIntList(key string) ([]int, error) {
+	str, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(str, ",")
+	res := make([]int, 0, len(parts))
+	for _, p := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrEnvNotCorrect, key)
+		}
+
+		res = append(res, v)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,17 @@
+package events
+
+import "context"
+
+type updateIDKey struct{}
+
+// WithUpdateID — remembers the telegram update that triggered the processing,
+// storages use it to link their writes to the update
+func WithUpdateID(ctx context.Context, updateID int) context.Context {
+	return context.WithValue(ctx, updateIDKey{}, updateID)
+}
+
+// UpdateID — telegram update ID stored by WithUpdateID, 0 when there is none
+func UpdateID(ctx context.Context) int {
+	id, _ := ctx.Value(updateIDKey{}).(int)
+	return id
+}
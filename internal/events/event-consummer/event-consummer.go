@@ -2,38 +2,71 @@ package eventconsummer
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
 	"time"
 
+	"drillCore/internal/bot"
 	"drillCore/internal/events"
 	eventprocessor "drillCore/internal/events/event-processor"
+	"drillCore/internal/storage/pg"
 
 	"go.uber.org/zap"
 )
 
+// Retry — how many times an event is processed before it goes to the dead letters,
+// the pause between attempts starts at Backoff and doubles
+type Retry struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
 type Consumer struct {
-	fetcher   events.Fetcher
-	processor events.Processor
-	batchSize int
+	fetcher     events.Fetcher
+	processor   events.Processor
+	deadLetters events.DeadLetterer
+	batchSize   int
+	retry       Retry
+
+	beat *atomic.Int64 // unix nano of the last loop iteration or processed event
 
 	logger *zap.SugaredLogger
 }
 
-func New(fetcher events.Fetcher, processor events.Processor, batchSize int, logger *zap.SugaredLogger) Consumer {
+func New(fetcher events.Fetcher, processor events.Processor, deadLetters events.DeadLetterer, batchSize int, retry Retry, logger *zap.SugaredLogger) Consumer {
+	beat := &atomic.Int64{}
+	beat.Store(time.Now().UnixNano())
+
 	return Consumer{
-		fetcher:   fetcher,
-		processor: processor,
-		batchSize: batchSize,
-		logger:    logger,
+		fetcher:     fetcher,
+		processor:   processor,
+		deadLetters: deadLetters,
+		batchSize:   batchSize,
+		retry:       retry,
+		beat:        beat,
+		logger:      logger,
 	}
 }
 
+// LastBeat — time the consumer loop last made progress
+func (c *Consumer) LastBeat() time.Time {
+	return time.Unix(0, c.beat.Load())
+}
+
 func (c *Consumer) Start(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
+			c.beat.Store(time.Now().UnixNano())
+
 			gotEvents, err := c.fetcher.Fetch(ctx, c.batchSize)
 			if err != nil {
 				if errors.Is(err, eventprocessor.ErrNoUpdatesFound) {
@@ -43,12 +76,14 @@ func (c *Consumer) Start(ctx context.Context) error {
 				}
 
 				c.logger.Errorw("failed to fetch events", "error", err)
+				time.Sleep(1 * time.Second)
 
 				continue
 			}
 
 			if err := c.handleEvents(ctx, gotEvents); err != nil {
 				c.logger.Errorw("failed to handle events", "error", err)
+				time.Sleep(1 * time.Second)
 
 				continue
 			}
@@ -62,15 +97,101 @@ func (c *Consumer) handleEvents(ctx context.Context, event []*events.Event) erro
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			c.logger.Infow("processing event", "event", e)
-
-			if err := c.processor.Process(ctx, e); err != nil {
-				c.logger.Errorw("failed to process event", "event", e, "error", err)
+			if e == nil || e.Meta == nil {
+				c.logger.Warnw("skipped empty event", "event", e)
 
 				continue
 			}
+
+			c.logger.Infow("processing event", "update_id", e.Meta.UpdateID, "type", e.Type.String(), "text", e.Text)
+
+			// the rest of the batch is dropped, it is fetched again from the committed offset
+			if err := c.process(ctx, e); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
+
+// process — processes the event, only transient errors are retried. The event that failed every attempt
+// or failed with a permanent error goes to the dead letters.
+func (c *Consumer) process(ctx context.Context, e *events.Event) error {
+	backoff := c.retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		c.beat.Store(time.Now().UnixNano())
+
+		err := c.processor.Process(ctx, e)
+		switch {
+		case err == nil:
+			return nil
+
+		case errors.Is(err, eventprocessor.ErrUnknownEventType):
+			c.logger.Warnw("skipped unsupported update", "update_id", e.Meta.UpdateID)
+
+			return nil
+
+		case ctx.Err() != nil:
+			return ctx.Err()
+		}
+
+		if attempt >= c.retry.Attempts || !transient(err) {
+			if dlErr := c.deadLetters.DeadLetter(ctx, e, err, attempt); dlErr != nil {
+				return fmt.Errorf("failed to process update %d: %w", e.Meta.UpdateID, errors.Join(err, dlErr))
+			}
+
+			return nil
+		}
+
+		// telegram tells how long the flood wait is, retrying earlier fails again
+		wait := backoff
+		if retryAfter, ok := bot.RetryAfter(err); ok {
+			wait = max(wait, retryAfter)
+		}
+
+		c.logger.Warnw("failed to process event, retrying",
+			"update_id", e.Meta.UpdateID,
+			"attempt", attempt,
+			"backoff", wait,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+	}
+}
+
+// transient — errors that may pass on the next attempt: the network, telegram 5xx and flood waits,
+// a lost or timed out database. Everything else, a panic included, fails the same way again.
+func transient(err error) bool {
+	if _, ok := bot.RetryAfter(err); ok {
+		return true
+	}
+
+	var apiErr *bot.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone):
+		return true
+	default:
+		return pg.Transient(err)
+	}
+}
@@ -0,0 +1,109 @@
+package eventconsummer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/events"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+type processor struct {
+	errs  []error // returned by the calls in order, nil once they run out
+	calls int
+}
+
+func (p *processor) Process(_ context.Context, _ *events.Event) error {
+	p.calls++
+	if p.calls > len(p.errs) {
+		return nil
+	}
+
+	return p.errs[p.calls-1]
+}
+
+type deadLetters struct {
+	attempts []int
+}
+
+func (d *deadLetters) DeadLetter(_ context.Context, _ *events.Event, _ error, attempts int) error {
+	d.attempts = append(d.attempts, attempts)
+	return nil
+}
+
+func TestProcessRetries(t *testing.T) {
+	const attempts = 3
+
+	transientErr := fmt.Errorf("failed to send message: %w", &bot.APIError{Code: http.StatusBadGateway})
+	permanentErr := fmt.Errorf("failed to send message: %w", &bot.APIError{Code: http.StatusBadRequest})
+
+	cases := []struct {
+		name       string
+		errs       []error
+		wantCalls  int
+		deadLetter bool
+	}{
+		{"success", nil, 1, false},
+		{"transient then success", []error{transientErr}, 2, false},
+		{"transient every attempt", []error{transientErr, transientErr, transientErr}, attempts, true},
+		{"permanent", []error{permanentErr}, 1, true},
+		{"panic", []error{events.ErrPanic}, 1, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &processor{errs: tc.errs}
+			dl := &deadLetters{}
+			c := New(nil, p, dl, 1, Retry{Attempts: attempts, Backoff: time.Millisecond}, zap.NewNop().Sugar())
+
+			if err := c.process(t.Context(), &events.Event{Meta: &events.Meta{UpdateID: 1}}); err != nil {
+				t.Fatalf("process: %v", err)
+			}
+
+			if p.calls != tc.wantCalls {
+				t.Fatalf("processed %d times, want %d", p.calls, tc.wantCalls)
+			}
+
+			if got := len(dl.attempts) == 1; got != tc.deadLetter {
+				t.Fatalf("dead letters %v, want dead letter: %v", dl.attempts, tc.deadLetter)
+			}
+			if tc.deadLetter && dl.attempts[0] != tc.wantCalls {
+				t.Fatalf("dead letter after %d attempts, want %d", dl.attempts[0], tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTransient(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"flood wait", &bot.APIError{Code: http.StatusTooManyRequests, RetryAfter: time.Second}, true},
+		{"telegram 5xx", &bot.APIError{Code: http.StatusInternalServerError}, true},
+		{"blocked by the user", &bot.APIError{Code: http.StatusForbidden}, false},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"timeout", context.DeadlineExceeded, true},
+		{"postgres connection lost", &pgconn.PgError{Code: "08006"}, true},
+		{"postgres check violation", &pgconn.PgError{Code: "23514"}, false},
+		{"panic", events.ErrPanic, false},
+		{"session lost", errors.New("session not found"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := transient(fmt.Errorf("failed to process: %w", tc.err)); got != tc.want {
+				t.Fatalf("transient(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,23 @@ package eventprocessor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
+	"drillCore/internal/access"
 	"drillCore/internal/bot"
 	"drillCore/internal/events"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/locale"
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+	"drillCore/internal/ratelimit"
+	"drillCore/internal/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -15,11 +26,46 @@ type HandlerManager interface {
 	HandleEvent(ctx context.Context, event *events.Event) error
 }
 
+// UpdateStorage — persisted polling offset, processed update IDs and dead letters
+type UpdateStorage interface {
+	Offset(ctx context.Context) (int, error)
+	IsProcessed(ctx context.Context, updateID int) (bool, error)
+	Commit(ctx context.Context, updateID int) error
+
+	CommitDeadLetter(ctx context.Context, dl *model.DeadLetter) (int64, error)
+	ReplayQueue(ctx context.Context, limit int) ([]*model.DeadLetter, error)
+	FailDeadLetter(ctx context.Context, id int64, errText string, attempts int) error
+	DeleteDeadLetter(ctx context.Context, id int64) error
+}
+
+// Authorizer — access control, events of users it rejects never reach the handlers
+type Authorizer interface {
+	Check(userID int) error
+}
+
+// Limiter — flood protection, events it does not allow never reach the handlers
+type Limiter interface {
+	Allow(userID int) ratelimit.Decision
+}
+
+// Registry — users known to the bot, the profile is refreshed by every event that passed the access
+// and flood checks, the storage skips the write when nothing changed
+type Registry interface {
+	SaveUser(ctx context.Context, u *model.User) error
+}
+
 type Processor struct {
 	tg         *bot.Client
+	updates    UpdateStorage
+	access     Authorizer
+	limiter    Limiter
+	users      Registry
 	offset     int
+	restored   bool // offset was loaded from updates
 	handlerMng HandlerManager
 	logger     *zap.SugaredLogger
+
+	lastPoll atomic.Int64 // unix nano of the last successful getUpdates
 }
 
 var (
@@ -28,49 +74,314 @@ var (
 	ErrInvalidCommand   = errors.New("invalid command")
 )
 
-func New(tg *bot.Client, hm HandlerManager, logger *zap.SugaredLogger) *Processor {
+func New(tg *bot.Client, hm HandlerManager, updates UpdateStorage, access Authorizer, limiter Limiter, users Registry, logger *zap.SugaredLogger) *Processor {
 	p := &Processor{
 		tg:         tg,
+		updates:    updates,
+		access:     access,
+		limiter:    limiter,
+		users:      users,
 		logger:     logger,
 		handlerMng: hm,
 	}
+	p.lastPoll.Store(time.Now().UnixNano())
 
 	return p
 }
 
+// Fetch — updates from the last committed offset, the offset moves only in Process,
+// so updates that failed are fetched again
 func (p *Processor) Fetch(ctx context.Context, limit int) ([]*events.Event, error) {
+	if !p.restored {
+		offset, err := p.updates.Offset(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore polling offset: %w", err)
+		}
+
+		p.offset, p.restored = offset, true
+		metrics.PollingOffset.Set(float64(p.offset))
+	}
+
+	replays, err := p.replays(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(replays) > 0 {
+		return replays, nil
+	}
+
 	updates, err := p.tg.Updates(ctx, p.offset, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updates: %w", err)
 	}
 
+	p.lastPoll.Store(time.Now().UnixNano())
+
 	if len(updates) == 0 {
 		return nil, fmt.Errorf("no updates found :%w", ErrNoUpdatesFound)
 	}
 
 	res := make([]*events.Event, 0, len(updates))
 	for _, u := range updates {
+		metrics.UpdatesFetched.WithLabelValues(p.fetchType(u).String()).Inc()
+
 		e, err := p.event(u)
 		if err != nil {
 			p.logger.Warnw("failed to fetch event", "event", u, "error", err)
 		}
 
+		if e == nil {
+			continue
+		}
+
 		res = append(res, e)
 	}
 
-	p.offset = updates[len(updates)-1].ID + 1
+	return res, nil
+}
+
+// replays — dead letters queued by the admins, they go before the new updates
+func (p *Processor) replays(ctx context.Context, limit int) ([]*events.Event, error) {
+	letters, err := p.updates.ReplayQueue(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replay queue: %w", err)
+	}
+
+	res := make([]*events.Event, 0, len(letters))
+	for _, dl := range letters {
+		var upd bot.Update
+		if err := json.Unmarshal(dl.Update, &upd); err != nil {
+			p.logger.Errorw("failed to decode dead letter", "dead_letter", dl.ID, "error", err)
+
+			if err := p.updates.FailDeadLetter(ctx, dl.ID, fmt.Sprintf("failed to decode update: %v", err), 0); err != nil {
+				return nil, fmt.Errorf("failed to fail dead letter %d: %w", dl.ID, err)
+			}
+
+			continue
+		}
+
+		e, err := p.event(upd)
+		if err != nil {
+			p.logger.Warnw("failed to fetch event", "event", upd, "error", err)
+		}
+
+		e.DeadLetterID = dl.ID
+		res = append(res, e)
+	}
 
 	return res, nil
 }
 
-func (p *Processor) Process(ctx context.Context, e *events.Event) error {
-	p.logger.Debugf("processing event: %s", e.Text)
+// LastPoll — time of the last successful getUpdates, the start time before the first one
+func (p *Processor) LastPoll() time.Time {
+	return time.Unix(0, p.lastPoll.Load())
+}
+
+func (p *Processor) Process(ctx context.Context, e *events.Event) (err error) {
+	if e == nil || e.Meta == nil {
+		return fmt.Errorf("failed to process empty event: %w", ErrUnknownEventType)
+	}
+
+	ctx, span := tracing.Start(ctx, "update",
+		attribute.Int("update.id", e.Meta.UpdateID),
+		attribute.String("update.type", e.Type.String()),
+		attribute.Int("chat.id", e.Meta.ChatID),
+		attribute.Int("user.id", e.Meta.UserID),
+	)
+
+	p.logger.Debugw("processing event", "text", e.Text, "update_id", e.Meta.UpdateID, "trace_id", tracing.TraceID(ctx))
+
+	defer func() {
+		metrics.UpdatesProcessed.WithLabelValues(e.Type.String(), metrics.Result(err)).Inc()
+		tracing.End(span, err)
+	}()
+
+	if e.DeadLetterID != 0 {
+		return p.replay(ctx, e)
+	}
+
+	processed, err := p.updates.IsProcessed(ctx, e.Meta.UpdateID)
+	if err != nil {
+		return fmt.Errorf("failed to process event: %w", err)
+	}
+
+	if processed {
+		p.logger.Infow("skipping already processed update", "update_id", e.Meta.UpdateID)
+
+		return p.commit(ctx, e)
+	}
 
 	if e.Type == events.Unknown {
+		if err := p.commit(ctx, e); err != nil {
+			return err
+		}
+
 		return fmt.Errorf("failed to process event: %w", ErrUnknownEventType)
 	}
 
-	return p.handlerMng.HandleEvent(ctx, e)
+	if err := p.authorize(e); err != nil {
+		return p.commit(ctx, e)
+	}
+
+	if !p.allow(ctx, e) {
+		return p.commit(ctx, e)
+	}
+
+	p.register(ctx, e)
+
+	ctx = events.WithUpdateID(ctx, e.Meta.UpdateID)
+
+	if err := p.handlerMng.HandleEvent(ctx, e); err != nil {
+		return err
+	}
+
+	return p.commit(ctx, e)
+}
+
+// register — saves the sender to the registry, a failure does not stop the event,
+// the user is saved on the next one. Locale and time zone are only a guess from the language.
+func (p *Processor) register(ctx context.Context, e *events.Event) {
+	loc, tz := locale.Guess(e.Meta.From.LanguageCode)
+	u := &model.User{
+		ID:           int64(e.Meta.UserID),
+		ChatID:       int64(e.Meta.ChatID),
+		Username:     e.Meta.From.Username,
+		FirstName:    e.Meta.From.FirstName,
+		LastName:     e.Meta.From.LastName,
+		LanguageCode: e.Meta.From.LanguageCode,
+		Locale:       loc,
+		TimeZone:     tz,
+	}
+
+	if err := p.users.SaveUser(ctx, u); err != nil {
+		p.logger.Errorw("failed to register user", "user_id", e.Meta.UserID, "error", err)
+	}
+}
+
+// authorize — rejects the events of banned and not allowed users, they are dropped silently
+func (p *Processor) authorize(e *events.Event) error {
+	err := p.access.Check(e.Meta.UserID)
+	if err == nil {
+		return nil
+	}
+
+	metrics.AccessDenied.WithLabelValues(accessReason(err)).Inc()
+	p.logger.Warnw("event rejected", "user_id", e.Meta.UserID, "update_id", e.Meta.UpdateID, "reason", err)
+
+	return err
+}
+
+// allow — flood protection, checked before anything touches the storages or telegram: over the limit
+// the user gets one throttle notice, then their events are dropped silently until the window lets them in again
+func (p *Processor) allow(ctx context.Context, e *events.Event) bool {
+	decision := p.limiter.Allow(e.Meta.UserID)
+	if decision == ratelimit.Allow {
+		return true
+	}
+
+	metrics.RateLimited.WithLabelValues(decision.String()).Inc()
+
+	if decision == ratelimit.Drop {
+		return false
+	}
+
+	p.logger.Warnw("user is throttled", "user_id", e.Meta.UserID, "update_id", e.Meta.UpdateID)
+
+	// the notice is a courtesy, the event is dropped either way
+	if err := p.tg.SendMessage(ctx, e.Meta.ChatID, manager.MsgThrottled); err != nil {
+		p.logger.Warnw("failed to send throttle notice", "user_id", e.Meta.UserID, "error", err)
+	}
+
+	return false
+}
+
+func accessReason(err error) string {
+	switch {
+	case errors.Is(err, access.ErrBanned):
+		return "banned"
+	case errors.Is(err, access.ErrNotAllowed):
+		return "not_allowed"
+	default:
+		return "other"
+	}
+}
+
+// replay — handles the dead letter again, its update is committed already
+func (p *Processor) replay(ctx context.Context, e *events.Event) error {
+	// nothing to retry, the dead letter goes back to the admins with the reason
+	var reject error
+	if e.Type == events.Unknown {
+		reject = ErrUnknownEventType
+	} else {
+		reject = p.authorize(e)
+	}
+
+	if reject != nil {
+		if err := p.updates.FailDeadLetter(ctx, e.DeadLetterID, reject.Error(), 0); err != nil {
+			return fmt.Errorf("failed to fail dead letter %d: %w", e.DeadLetterID, err)
+		}
+
+		return nil
+	}
+
+	ctx = events.WithUpdateID(ctx, e.Meta.UpdateID)
+
+	if err := p.handlerMng.HandleEvent(ctx, e); err != nil {
+		return err
+	}
+
+	if err := p.updates.DeleteDeadLetter(ctx, e.DeadLetterID); err != nil {
+		return fmt.Errorf("failed to resolve dead letter %d: %w", e.DeadLetterID, err)
+	}
+
+	p.logger.Infow("dead letter replayed", "dead_letter", e.DeadLetterID, "update_id", e.Meta.UpdateID)
+
+	return nil
+}
+
+// DeadLetter — saves the event that failed every attempt and moves the offset past it,
+// a replayed dead letter goes back to the admins
+func (p *Processor) DeadLetter(ctx context.Context, e *events.Event, cause error, attempts int) error {
+	if e.DeadLetterID != 0 {
+		if err := p.updates.FailDeadLetter(ctx, e.DeadLetterID, cause.Error(), attempts); err != nil {
+			return fmt.Errorf("failed to fail dead letter %d: %w", e.DeadLetterID, err)
+		}
+
+		return nil
+	}
+
+	id, err := p.updates.CommitDeadLetter(ctx, &model.DeadLetter{
+		UpdateID: int64(e.Meta.UpdateID),
+		UserID:   int64(e.Meta.UserID),
+		ChatID:   int64(e.Meta.ChatID),
+		Update:   e.Raw,
+		Error:    cause.Error(),
+		Attempts: attempts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save dead letter of update %d: %w", e.Meta.UpdateID, err)
+	}
+
+	p.offset = max(p.offset, e.Meta.UpdateID+1)
+	metrics.PollingOffset.Set(float64(p.offset))
+	metrics.DeadLetters.Inc()
+
+	p.logger.Errorw("update moved to dead letters", "dead_letter", id, "update_id", e.Meta.UpdateID, "error", cause)
+
+	return nil
+}
+
+// commit — marks the update processed and moves the offset past it
+func (p *Processor) commit(ctx context.Context, e *events.Event) error {
+	if err := p.updates.Commit(ctx, e.Meta.UpdateID); err != nil {
+		return fmt.Errorf("failed to commit update %d: %w", e.Meta.UpdateID, err)
+	}
+
+	p.offset = max(p.offset, e.Meta.UpdateID+1)
+	metrics.PollingOffset.Set(float64(p.offset))
+
+	return nil
 }
 
 func (p *Processor) event(upd bot.Update) (*events.Event, error) {
@@ -78,30 +389,49 @@ func (p *Processor) event(upd bot.Update) (*events.Event, error) {
 
 	updType := p.fetchType(upd)
 
+	// the dead letter of an unmarshalable update just has no raw update to replay
+	raw, err := json.Marshal(upd)
+	if err != nil {
+		p.logger.Warnw("failed to marshal update", "update_id", upd.ID, "error", err)
+	}
+
 	res := events.Event{
 		Type: updType,
 		Text: fetchText(upd),
+		Raw:  raw,
 	}
 
-	var m events.Meta
+	m := events.Meta{UpdateID: upd.ID}
+	res.Meta = &m
+
 	switch updType {
 	case events.Message:
 		m.ChatID = upd.Message.Chat.ID
 		m.UserID = upd.Message.From.ID
+		m.From = from(upd.Message.From)
 	case events.Callback:
 		m.ChatID = upd.CallbackQuery.Message.Chat.ID
 		m.UserID = upd.CallbackQuery.From.ID
+		m.From = from(upd.CallbackQuery.From)
 	case events.Unknown:
-		return nil, ErrUnknownEventType
+		// still returned, Process has to commit it to move the offset past it
+		return &res, ErrUnknownEventType
 	}
 
-	res.Meta = &m
-
 	p.logger.Debugf("fetch event:%+v", res)
 
 	return &res, nil
 }
 
+func from(f bot.From) events.From {
+	return events.From{
+		Username:     f.Username,
+		FirstName:    f.FirstName,
+		LastName:     f.LastName,
+		LanguageCode: f.LanguageCode,
+	}
+}
+
 func (p *Processor) fetchType(upd bot.Update) events.Type {
 	switch {
 	case upd.Message != nil:
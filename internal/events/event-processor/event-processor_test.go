@@ -0,0 +1,368 @@
+package eventprocessor_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"drillCore/internal/access"
+	"drillCore/internal/bot"
+	"drillCore/internal/bot/fake"
+	"drillCore/internal/config"
+	"drillCore/internal/events"
+	eventprocessor "drillCore/internal/events/event-processor"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/events/event-processor/manager/debt"
+	"drillCore/internal/model"
+	"drillCore/internal/ratelimit"
+	"drillCore/internal/session"
+	debtSQLite "drillCore/internal/storage/debt/sqlite"
+	"drillCore/internal/storage/migrate"
+	"drillCore/internal/storage/sqlitedb"
+	"drillCore/internal/storage/update/memory"
+	updateSQLite "drillCore/internal/storage/update/sqlite"
+	"drillCore/migrations"
+
+	"go.uber.org/zap"
+)
+
+const (
+	pilotID  = 1
+	bannedID = 2
+)
+
+type handlerManager struct {
+	handled []int
+}
+
+func (m *handlerManager) HandleEvent(_ context.Context, e *events.Event) error {
+	m.handled = append(m.handled, e.Meta.UpdateID)
+	return nil
+}
+
+type guard struct{}
+
+func (guard) Check(userID int) error {
+	if userID == bannedID {
+		return access.ErrBanned
+	}
+
+	return nil
+}
+
+type registry struct {
+	saved []int64
+}
+
+func (r *registry) SaveUser(_ context.Context, u *model.User) error {
+	r.saved = append(r.saved, u.ID)
+	return nil
+}
+
+type env struct {
+	srv      *fake.Server
+	hm       *handlerManager
+	users    *registry
+	updates  *memory.UpdateStorage
+	p        *eventprocessor.Processor
+	updateID int
+}
+
+func newEnv(t *testing.T, limit int) *env {
+	t.Helper()
+
+	logger := zap.NewNop().Sugar()
+
+	srv := fake.New()
+	t.Cleanup(srv.Close)
+
+	env := &env{srv: srv, hm: &handlerManager{}, users: &registry{}, updates: memory.New()}
+
+	tg := bot.New(&config.TelegramEnvs{Token: "test", BaseUrl: srv.URL()}, logger)
+	limiter := ratelimit.New(limit, time.Hour)
+	env.p = eventprocessor.New(tg, env.hm, env.updates, guard{}, limiter, env.users, logger)
+
+	return env
+}
+
+// process — a new message of the user goes through the processor, returns its update ID
+func (env *env) process(t *testing.T, userID int) int {
+	t.Helper()
+
+	env.updateID++
+
+	err := env.p.Process(t.Context(), &events.Event{
+		Type: events.Message,
+		Text: "spin on",
+		Meta: &events.Meta{ChatID: userID, UserID: userID, UpdateID: env.updateID},
+	})
+	if err != nil {
+		t.Fatalf("process update %d: %v", env.updateID, err)
+	}
+
+	processed, err := env.updates.IsProcessed(t.Context(), env.updateID)
+	if err != nil {
+		t.Fatalf("is processed: %v", err)
+	}
+	if !processed {
+		t.Fatalf("update %d is not committed", env.updateID)
+	}
+
+	return env.updateID
+}
+
+func TestProcessRateLimit(t *testing.T) {
+	const limit = 2
+	env := newEnv(t, limit)
+
+	var allowed []int
+	for range limit {
+		allowed = append(allowed, env.process(t, pilotID))
+	}
+
+	// the first event over the limit gets the notice, the rest are dropped silently
+	for range 3 {
+		env.process(t, pilotID)
+	}
+
+	if len(env.hm.handled) != limit || env.hm.handled[0] != allowed[0] || env.hm.handled[1] != allowed[1] {
+		t.Fatalf("handled updates %v, want %v", env.hm.handled, allowed)
+	}
+
+	if len(env.users.saved) != limit {
+		t.Fatalf("registered the user %d times, want %d: throttled events must not touch the registry", len(env.users.saved), limit)
+	}
+
+	select {
+	case m := <-env.srv.Messages():
+		if m.ChatID != pilotID || m.Text != manager.MsgThrottled {
+			t.Fatalf("got message %+v, want the throttle notice", m)
+		}
+	default:
+		t.Fatal("no throttle notice")
+	}
+
+	select {
+	case m := <-env.srv.Messages():
+		t.Fatalf("unexpected message %q", m.Text)
+	default:
+	}
+
+	// other users have limits of their own
+	env.process(t, pilotID+2)
+	if len(env.hm.handled) != limit+1 {
+		t.Fatalf("event of another user was not handled")
+	}
+}
+
+func TestProcessAccessDenied(t *testing.T) {
+	env := newEnv(t, 1)
+
+	for range 3 {
+		env.process(t, bannedID)
+	}
+
+	if len(env.hm.handled) != 0 || len(env.users.saved) != 0 {
+		t.Fatalf("events of a banned user reached the handlers %v or the registry %v", env.hm.handled, env.users.saved)
+	}
+
+	select {
+	case m := <-env.srv.Messages():
+		t.Fatalf("banned user got %q", m.Text)
+	default:
+	}
+}
+
+var errCommit = errors.New("connection lost")
+
+// flakyCommit — update storage that fails the commit of one update, as if the bot crashed
+// after the handler wrote its side effect
+type flakyCommit struct {
+	*updateSQLite.UpdateStorage
+	fail int
+}
+
+func (f *flakyCommit) Commit(ctx context.Context, updateID int) error {
+	if updateID == f.fail {
+		return errCommit
+	}
+
+	return f.UpdateStorage.Commit(ctx, updateID)
+}
+
+// pilot — a user talking to the real debt handler through the processor, debts and updates share a database
+type pilot struct {
+	t        *testing.T
+	srv      *fake.Server
+	p        *eventprocessor.Processor
+	updates  *flakyCommit
+	debts    *debtSQLite.DebtStorage
+	updateID int
+
+	last fake.Message
+}
+
+func newPilot(t *testing.T) *pilot {
+	t.Helper()
+
+	logger := zap.NewNop().Sugar()
+
+	db, err := sqlitedb.Open(t.Context(), &config.DbEnvs{
+		Driver:           config.DriverSQLite,
+		Path:             filepath.Join(t.TempDir(), "drillcore.db"),
+		StatementTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrate.NewSQLite(db, migrations.SQLite(), logger)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	if err := m.Up(t.Context()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	srv := fake.New()
+	t.Cleanup(srv.Close)
+
+	tg := bot.New(&config.TelegramEnvs{Token: "test", BaseUrl: srv.URL()}, logger)
+	sMng := session.New()
+	debts := debtSQLite.New(db, logger)
+	updates := &flakyCommit{UpdateStorage: updateSQLite.New(db, logger)}
+
+	mng := manager.New(tg, sMng, logger,
+		[]manager.Middleware{manager.Settings(debts, logger, manager.DebtHandler)},
+		debt.New(tg, sMng, debts, time.Hour, logger),
+	)
+	p := eventprocessor.New(tg, mng, updates, guard{}, ratelimit.New(100, time.Hour), &registry{}, logger)
+
+	return &pilot{t: t, srv: srv, p: p, updates: updates, debts: debts}
+}
+
+// next — the next update of the pilot
+func (p *pilot) next(typ events.Type, text string) *events.Event {
+	p.updateID++
+
+	return &events.Event{
+		Type: typ,
+		Text: text,
+		Meta: &events.Meta{ChatID: pilotID, UserID: pilotID, UpdateID: p.updateID},
+	}
+}
+
+// process — passes the event to the processor and keeps the reply
+func (p *pilot) process(e *events.Event) error {
+	p.t.Helper()
+
+	err := p.p.Process(p.t.Context(), e)
+
+	select {
+	case p.last = <-p.srv.Messages():
+	default:
+		p.t.Fatalf("no reply to update %d %q", e.Meta.UpdateID, e.Text)
+	}
+
+	return err
+}
+
+// press — the event of the button of the last message
+func (p *pilot) press(text string) *events.Event {
+	p.t.Helper()
+
+	for _, row := range p.last.Markup.InlineKeyboard {
+		for _, b := range row {
+			if b.Text == text {
+				return p.next(events.Callback, b.CallbackData)
+			}
+		}
+	}
+
+	p.t.Fatalf("no button %q in the last message %q", text, p.last.Text)
+	return nil
+}
+
+func (p *pilot) must(e *events.Event) {
+	p.t.Helper()
+
+	if err := p.process(e); err != nil {
+		p.t.Fatalf("process update %d %q: %v", e.Meta.UpdateID, e.Text, err)
+	}
+}
+
+// TestProcessHandledUpdate — the payment was stored, but the update was not committed:
+// processing it again must not pay twice
+func TestProcessHandledUpdate(t *testing.T) {
+	p := newPilot(t)
+
+	d := &model.Debt{UserID: pilotID, Description: "drill", Amount: 1000}
+	id, err := p.debts.Save(t.Context(), d)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	start, err := manager.CreateCallBack(manager.DebtHandler, manager.StepStart, "")
+	if err != nil {
+		t.Fatalf("create callback: %v", err)
+	}
+
+	p.must(p.next(events.Callback, start))
+	p.must(p.press(manager.PayDebtButton))
+	p.must(p.press("🌀 drill - 1.000₽"))
+	p.must(p.press(manager.RedirectDebtButton))
+	p.must(p.next(events.Message, "400"))
+
+	confirm := p.press(manager.ConfirmButton)
+	p.updates.fail = confirm.Meta.UpdateID
+
+	if err := p.process(confirm); !errors.Is(err, errCommit) {
+		t.Fatalf("got error %v, want %v", err, errCommit)
+	}
+
+	processed, err := p.updates.IsProcessed(t.Context(), confirm.Meta.UpdateID)
+	if err != nil {
+		t.Fatalf("is processed: %v", err)
+	}
+	if !processed {
+		t.Fatal("update with a stored payment is not processed")
+	}
+
+	// telegram delivers the update again, it is committed without reaching the handler
+	p.updates.fail = 0
+	if err := p.p.Process(t.Context(), confirm); err != nil {
+		t.Fatalf("process the update again: %v", err)
+	}
+
+	select {
+	case m := <-p.srv.Messages():
+		t.Fatalf("handled update got a second reply %q", m.Text)
+	default:
+	}
+
+	trail, err := p.debts.AuditTrail(t.Context(), pilotID, 10)
+	if err != nil {
+		t.Fatalf("audit trail: %v", err)
+	}
+
+	var pays int
+	for _, e := range trail {
+		if e.Action == model.AuditPay {
+			pays++
+		}
+	}
+	if pays != 1 {
+		t.Fatalf("got %d payments in the audit trail, want 1", pays)
+	}
+
+	got, err := p.debts.Debt(t.Context(), pilotID, id)
+	if err != nil {
+		t.Fatalf("debt: %v", err)
+	}
+	if got.Amount != 600 {
+		t.Fatalf("amount is %d, want 600", got.Amount)
+	}
+}
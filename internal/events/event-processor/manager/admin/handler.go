@@ -0,0 +1,518 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"drillCore/internal/access"
+	"drillCore/internal/bot"
+	"drillCore/internal/events"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+	"drillCore/internal/session"
+	updateStorage "drillCore/internal/storage/update"
+
+	"go.uber.org/zap"
+)
+
+type Storage interface {
+	AuditTrail(ctx context.Context, userID int64, limit int) ([]*model.AuditEvent, error)
+	DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error)
+	Stats(ctx context.Context) (*model.DebtStats, error)
+}
+
+// Users — registry of the users who ever wrote to the bot
+type Users interface {
+	UserStats(ctx context.Context) (*model.UserStats, error)
+}
+
+// Broadcaster — queues the broadcasts, they are delivered in the background
+type Broadcaster interface {
+	Broadcast(ctx context.Context, text string, adminID, chatID int) (*model.Broadcast, error)
+	Broadcasts(ctx context.Context, limit int) ([]*model.Broadcast, error)
+}
+
+type SessionManager interface {
+	Get(ctx context.Context, userID int) (*session.Session, bool)
+	Set(ctx context.Context, userID int, s *session.Session) error
+	Delete(ctx context.Context, userID int) error
+	Len() int
+	UserIDs() []int
+}
+
+type DeadLetters interface {
+	DeadLetters(ctx context.Context, limit int) ([]*model.DeadLetter, error)
+	RequeueDeadLetter(ctx context.Context, id int64) error
+	DeleteDeadLetter(ctx context.Context, id int64) error
+}
+
+// Access — roles and access rules of the users
+type Access interface {
+	IsAdmin(userID int) bool
+	Allowlist() bool
+	Ban(ctx context.Context, adminID, userID int) error
+	Unban(ctx context.Context, adminID, userID int) error
+	Allow(ctx context.Context, adminID, userID int) error
+	Revoke(ctx context.Context, adminID, userID int) error
+}
+
+const (
+	auditLimit      = 20
+	deadLetterLimit = 20
+	userDebtLimit   = 10
+	sessionsLimit   = 30
+	broadcastLimit  = 10
+)
+
+// Handler — commands of the bot admins, for everyone else they do not exist
+type Handler struct {
+	tg          *bot.Client
+	sesMng      SessionManager
+	storage     Storage
+	deadLetters DeadLetters
+	users       Users
+	access      Access
+	broadcasts  Broadcaster
+	logger      *zap.SugaredLogger
+
+	broadcastKB bot.ReplyMarkup
+}
+
+func New(
+	tg *bot.Client,
+	sm SessionManager,
+	storage Storage,
+	deadLetters DeadLetters,
+	users Users,
+	access Access,
+	broadcasts Broadcaster,
+	logger *zap.SugaredLogger,
+) *Handler {
+	h := &Handler{
+		tg:          tg,
+		sesMng:      sm,
+		storage:     storage,
+		deadLetters: deadLetters,
+		users:       users,
+		access:      access,
+		broadcasts:  broadcasts,
+		logger:      logger,
+	}
+
+	kb, err := broadcastKeyboard()
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	h.broadcastKB = kb
+
+	return h
+}
+
+func (h *Handler) Type() manager.TypeHandler {
+	return manager.AdminHandler
+}
+
+func (h *Handler) Handle(ctx context.Context, e *events.Event) error {
+	h.logger.Debugw("handling event in ", "handler", manager.AdminHandler, "event", e)
+
+	if !h.access.IsAdmin(e.Meta.UserID) {
+		h.logger.Warnw("admin command from non admin user", "user", e.Meta.UserID, "text", e.Text)
+
+		return h.tg.SendMessage(ctx, e.Meta.ChatID, manager.InvalidCommand)
+	}
+
+	switch e.Type {
+	case events.Message:
+	case events.Callback:
+		return h.handleCallBack(ctx, e)
+	default:
+		return h.tg.SendMessage(ctx, e.Meta.ChatID, manager.InvalidEventType)
+	}
+
+	cmd, _ := manager.ParseCommand(e.Text)
+	args := manager.CommandArgs(e.Text)
+
+	switch cmd {
+	case manager.Audit:
+		return h.audit(ctx, e.Meta.ChatID, args)
+
+	case manager.DeadLetters:
+		return h.listDeadLetters(ctx, e.Meta.ChatID)
+
+	case manager.Replay, manager.Discard:
+		return h.changeDeadLetter(ctx, e.Meta.ChatID, cmd, args)
+
+	case manager.Ban, manager.Unban, manager.Allow, manager.Revoke:
+		return h.changeAccess(ctx, e.Meta.ChatID, e.Meta.UserID, cmd, args)
+
+	case manager.Stats:
+		return h.stats(ctx, e.Meta.ChatID)
+
+	case manager.User:
+		return h.user(ctx, e.Meta.ChatID, args)
+
+	case manager.Sessions:
+		return h.sessions(ctx, e.Meta.ChatID, args)
+
+	case manager.Broadcast:
+		return h.previewBroadcast(ctx, e.Meta.ChatID, e.Meta.UserID, e.Text)
+
+	case manager.Broadcasts:
+		return h.listBroadcasts(ctx, e.Meta.ChatID)
+
+	default:
+		return h.tg.SendMessage(ctx, e.Meta.ChatID, manager.InvalidCommand)
+	}
+}
+
+func (h *Handler) audit(ctx context.Context, chatID int, args []string) error {
+	if len(args) != 1 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgAuditUsage)
+	}
+
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgAuditUsage)
+	}
+
+	trail, err := h.storage.AuditTrail(ctx, userID, auditLimit)
+	if err != nil {
+		h.logger.Errorf("failed to get audit trail of user %d: %v", userID, err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetAudit)
+	}
+
+	if len(trail) == 0 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgAuditEmpty)
+	}
+
+	return h.tg.SendMessage(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgAuditTrailFormat, userID, auditLimit, auditTable(trail)),
+		bot.WithHTML(),
+	)
+}
+
+func (h *Handler) listDeadLetters(ctx context.Context, chatID int) error {
+	letters, err := h.deadLetters.DeadLetters(ctx, deadLetterLimit)
+	if err != nil {
+		h.logger.Errorf("failed to get dead letters: %v", err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetDeadLetters)
+	}
+
+	if len(letters) == 0 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgDeadLettersEmpty)
+	}
+
+	return h.tg.SendMessage(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgDeadLettersFormat, deadLetterLimit, deadLetterTable(letters)),
+		bot.WithHTML(),
+	)
+}
+
+// changeDeadLetter — /replay queues the dead letter for the consumer, /discard drops it
+func (h *Handler) changeDeadLetter(ctx context.Context, chatID int, cmd manager.ReservedCommand, args []string) error {
+	if len(args) != 1 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgDeadLetterUsage)
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgDeadLetterUsage)
+	}
+
+	msg := manager.MsgDeadLetterReplayQueued
+	if cmd == manager.Discard {
+		msg = manager.MsgDeadLetterDiscarded
+		err = h.deadLetters.DeleteDeadLetter(ctx, id)
+	} else {
+		err = h.deadLetters.RequeueDeadLetter(ctx, id)
+	}
+
+	switch {
+	case errors.Is(err, updateStorage.ErrDeadLetterNotFound):
+		return h.tg.SendMessage(ctx, chatID, fmt.Sprintf(manager.MsgDeadLetterNotFound, id))
+
+	case err != nil:
+		h.logger.Errorf("failed to %s dead letter %d: %v", cmd, id, err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetDeadLetters)
+	}
+
+	h.logger.Infow("dead letter changed by admin", "command", cmd, "dead_letter", id)
+
+	return h.tg.SendMessage(ctx, chatID, fmt.Sprintf(msg, id))
+}
+
+func (h *Handler) changeAccess(ctx context.Context, chatID, adminID int, cmd manager.ReservedCommand, args []string) error {
+	if len(args) != 1 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgAccessUsage)
+	}
+
+	userID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgAccessUsage)
+	}
+
+	change := map[manager.ReservedCommand]func(ctx context.Context, adminID, userID int) error{
+		manager.Ban:    h.access.Ban,
+		manager.Unban:  h.access.Unban,
+		manager.Allow:  h.access.Allow,
+		manager.Revoke: h.access.Revoke,
+	}[cmd]
+
+	err = change(ctx, adminID, userID)
+
+	switch {
+	case errors.Is(err, access.ErrAdminImmune):
+		return h.tg.SendMessage(ctx, chatID, fmt.Sprintf(manager.MsgAccessAdminImmune, userID))
+
+	case err != nil:
+		h.logger.Errorf("failed to %s user %d: %v", cmd, userID, err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToChangeAccess)
+	}
+
+	msg := fmt.Sprintf(manager.MsgAccessChanged, strings.ToUpper(strings.TrimPrefix(string(cmd), "/")), userID)
+	if (cmd == manager.Allow || cmd == manager.Revoke) && !h.access.Allowlist() {
+		msg += manager.MsgAccessAllowlistOff
+	}
+
+	return h.tg.SendMessage(ctx, chatID, msg)
+}
+
+func (h *Handler) handleCallBack(ctx context.Context, e *events.Event) error {
+	cb, err := manager.ParseCallBack(e.Text)
+	if err != nil {
+		return h.tg.SendMessage(ctx, e.Meta.ChatID, manager.FailedToGetCallBack)
+	}
+
+	switch cb.Step {
+	case manager.StepBroadcastSend:
+		return h.sendBroadcast(ctx, e.Meta.ChatID, e.Meta.UserID)
+
+	case manager.StepBroadcastCancel:
+		if err := h.sesMng.Delete(ctx, e.Meta.UserID); err != nil {
+			h.logger.Errorf("failed to delete session of admin %d: %v", e.Meta.UserID, err)
+		}
+
+		return h.tg.SendMessage(ctx, e.Meta.ChatID, manager.MsgBroadcastCanceled)
+
+	default:
+		return h.tg.SendMessage(ctx, e.Meta.ChatID, fmt.Sprintf(manager.InvalidStep, cb.Step))
+	}
+}
+
+func (h *Handler) stats(ctx context.Context, chatID int) error {
+	st, err := h.storage.Stats(ctx)
+	if err != nil {
+		h.logger.Errorf("failed to get stats: %v", err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetStats)
+	}
+
+	users, err := h.users.UserStats(ctx)
+	if err != nil {
+		h.logger.Errorf("failed to get user stats: %v", err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetStats)
+	}
+
+	return h.tg.SendMessage(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgStatsFormat, statsTable(users, st, h.sesMng.Len(), metrics.Errors())),
+		bot.WithHTML(),
+	)
+}
+
+// user — first debts of the user and the state of the session
+func (h *Handler) user(ctx context.Context, chatID int, args []string) error {
+	if len(args) != 1 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgUserUsage)
+	}
+
+	userID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgUserUsage)
+	}
+
+	page, err := h.storage.DebtsPage(ctx, int64(userID), 0, userDebtLimit, nil)
+	if err != nil {
+		h.logger.Errorf("failed to get debts of user %d: %v", userID, err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetUser)
+	}
+
+	ses, _ := h.sesMng.Get(ctx, userID)
+
+	return h.tg.SendMessage(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgUserFormat, userID, userTable(page, ses)),
+		bot.WithHTML(),
+	)
+}
+
+// sessions — without args lists the active sessions, /sessions USER_ID clears the stuck one
+func (h *Handler) sessions(ctx context.Context, chatID int, args []string) error {
+	if len(args) > 1 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgSessionsUsage)
+	}
+
+	if len(args) == 1 {
+		userID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return h.tg.SendMessage(ctx, chatID, manager.MsgSessionsUsage)
+		}
+
+		if _, ok := h.sesMng.Get(ctx, userID); !ok {
+			return h.tg.SendMessage(ctx, chatID, fmt.Sprintf(manager.MsgSessionNotFound, userID))
+		}
+
+		if err := h.sesMng.Delete(ctx, userID); err != nil {
+			h.logger.Errorf("failed to delete session of user %d: %v", userID, err)
+
+			return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToGetUser)
+		}
+
+		h.logger.Infow("session cleared by admin", "user", userID)
+
+		return h.tg.SendMessage(ctx, chatID, fmt.Sprintf(manager.MsgSessionCleared, userID))
+	}
+
+	ids := h.sesMng.UserIDs()
+	if len(ids) == 0 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgSessionsEmpty)
+	}
+
+	total := len(ids)
+	ids = ids[:min(total, sessionsLimit)]
+
+	sessions := make(map[int]*session.Session, len(ids))
+	for _, id := range ids {
+		if ses, ok := h.sesMng.Get(ctx, id); ok {
+			sessions[id] = ses
+		}
+	}
+
+	return h.tg.SendMessage(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgSessionsFormat, total, sessionsTable(ids, sessions)),
+		bot.WithHTML(),
+	)
+}
+
+// previewBroadcast — shows the text as the users will get it, it is kept in the admin
+// session until the admin confirms or drops it
+func (h *Handler) previewBroadcast(ctx context.Context, chatID, adminID int, text string) error {
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), string(manager.Broadcast)))
+	if text == "" {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgBroadcastUsage)
+	}
+
+	users, err := h.users.UserStats(ctx)
+	if err != nil {
+		h.logger.Errorf("failed to count broadcast recipients: %v", err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToBroadcast)
+	}
+
+	now := time.Now()
+	err = h.sesMng.Set(ctx, adminID, &session.Session{
+		CreatedAt: &now,
+		UpdatedAt: &now,
+		State: &manager.State{
+			Handler:   manager.AdminHandler,
+			Step:      manager.StepBroadcastConfirm,
+			Broadcast: text,
+		},
+	})
+	if err != nil {
+		h.logger.Errorf("failed to set session of admin %d: %v", adminID, err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToSetSession)
+	}
+
+	return h.tg.SendMessageWithKeyboard(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgBroadcastPreviewFormat, users.Users-users.Blocked, text),
+		h.broadcastKB,
+	)
+}
+
+// sendBroadcast — queues the confirmed text for the broadcast job,
+// the session is dropped first so a second press does not queue it twice
+func (h *Handler) sendBroadcast(ctx context.Context, chatID, adminID int) error {
+	ses, ok := h.sesMng.Get(ctx, adminID)
+	if !ok {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgBroadcastExpired)
+	}
+
+	state, err := manager.ExtractState(ses)
+	if err != nil || state.Step != manager.StepBroadcastConfirm || state.Broadcast == "" {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgBroadcastExpired)
+	}
+
+	if err := h.sesMng.Delete(ctx, adminID); err != nil {
+		h.logger.Errorf("failed to delete session of admin %d: %v", adminID, err)
+	}
+
+	b, err := h.broadcasts.Broadcast(ctx, state.Broadcast, adminID, chatID)
+	if err != nil {
+		h.logger.Errorf("failed to queue broadcast of admin %d: %v", adminID, err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToBroadcast)
+	}
+
+	return h.tg.SendMessage(ctx, chatID, fmt.Sprintf(manager.MsgBroadcastQueuedFormat, b.ID, b.Total))
+}
+
+func (h *Handler) listBroadcasts(ctx context.Context, chatID int) error {
+	list, err := h.broadcasts.Broadcasts(ctx, broadcastLimit)
+	if err != nil {
+		h.logger.Errorf("failed to get broadcasts: %v", err)
+
+		return h.tg.SendMessage(ctx, chatID, manager.MsgFailedToBroadcast)
+	}
+
+	if len(list) == 0 {
+		return h.tg.SendMessage(ctx, chatID, manager.MsgBroadcastsEmpty)
+	}
+
+	return h.tg.SendMessage(
+		ctx,
+		chatID,
+		fmt.Sprintf(manager.MsgBroadcastsFormat, broadcastLimit, broadcastTable(list)),
+		bot.WithHTML(),
+	)
+}
+
+func broadcastKeyboard() (bot.ReplyMarkup, error) {
+	send, err := manager.CreateCallBack(manager.AdminHandler, manager.StepBroadcastSend, "")
+	if err != nil {
+		return bot.ReplyMarkup{}, fmt.Errorf("failed to create broadcast callback: %w", err)
+	}
+
+	cancel, err := manager.CreateCallBack(manager.AdminHandler, manager.StepBroadcastCancel, "")
+	if err != nil {
+		return bot.ReplyMarkup{}, fmt.Errorf("failed to create broadcast callback: %w", err)
+	}
+
+	return bot.NewInlineKeyboard([][]bot.InlineKeyboardButton{
+		{{Text: manager.BroadcastSendButton, CallbackData: send}},
+		{{Text: manager.CancelButton, CallbackData: cancel}},
+	}), nil
+}
@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+	"drillCore/internal/session"
+)
+
+// auditTable — one block per event: header line and the changed fields, ready for bot.ParseModeHTML
+func auditTable(trail []*model.AuditEvent) string {
+	var sb strings.Builder
+	for _, e := range trail {
+		fmt.Fprintf(&sb, "%s  %-6s  #%d  upd:%d\n",
+			e.CreatedAt.Format("02.01.2006 15:04:05"),
+			strings.ToUpper(string(e.Action)),
+			e.DebtID,
+			e.UpdateID,
+		)
+
+		for _, line := range auditChanges(e.Before, e.After) {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	return "<pre>" + bot.EscapeHTML(strings.TrimRight(sb.String(), "\n")) + "</pre>"
+}
+
+// deadLetterTable — one block per dead letter: header line, update text and the last error
+func deadLetterTable(letters []*model.DeadLetter) string {
+	var sb strings.Builder
+	for _, dl := range letters {
+		fmt.Fprintf(&sb, "#%d  %s  %-6s  user:%d  upd:%d  try:%d\n",
+			dl.ID,
+			dl.UpdatedAt.Format("02.01.2006 15:04:05"),
+			strings.ToUpper(string(dl.Status)),
+			dl.UserID,
+			dl.UpdateID,
+			dl.Attempts,
+		)
+
+		if text := deadLetterText(dl); text != "" {
+			sb.WriteString("  > " + shorten(text, 60) + "\n")
+		}
+
+		sb.WriteString("  ! " + shorten(dl.Error, 120) + "\n")
+	}
+
+	return "<pre>" + bot.EscapeHTML(strings.TrimRight(sb.String(), "\n")) + "</pre>"
+}
+
+// deadLetterText — message text or callback data of the raw update
+func deadLetterText(dl *model.DeadLetter) string {
+	var upd bot.Update
+	if err := json.Unmarshal(dl.Update, &upd); err != nil {
+		return ""
+	}
+
+	switch {
+	case upd.Message != nil:
+		return upd.Message.Text
+	case upd.CallbackQuery != nil:
+		return upd.CallbackQuery.Data
+	default:
+		return ""
+	}
+}
+
+// statsTable — totals of the storage, active sessions and error counters since the start
+func statsTable(users *model.UserStats, st *model.DebtStats, sessions int, errs metrics.ErrorCounts) string {
+	rows := []struct {
+		name  string
+		value int64
+	}{
+		{"users", int64(users.Users)},
+		{"blocked the bot", int64(users.Blocked)},
+		{"debt users", int64(st.Users)},
+		{"debts", int64(st.Debts)},
+		{"overdue debts", int64(st.Overdue)},
+		{"debt amount", st.Amount},
+		{"active sessions", int64(sessions)},
+		{"failed events", int64(errs.Failed)},
+		{"dead letters", int64(errs.DeadLetters)},
+		{"handler panics", int64(errs.Panics)},
+		{"telegram errors", int64(errs.Telegram)},
+	}
+
+	var sb strings.Builder
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "%-16s %d\n", r.name, r.value)
+	}
+
+	return "<pre>" + bot.EscapeHTML(strings.TrimRight(sb.String(), "\n")) + "</pre>"
+}
+
+// broadcastTable — one block per broadcast: counters of the deliveries and the start of the text
+func broadcastTable(list []*model.Broadcast) string {
+	var sb strings.Builder
+	for _, b := range list {
+		fmt.Fprintf(&sb, "#%d  %s  %-7s  by:%d\n",
+			b.ID,
+			b.CreatedAt.Format("02.01.2006 15:04:05"),
+			strings.ToUpper(string(b.Status)),
+			b.CreatedBy,
+		)
+
+		fmt.Fprintf(&sb, "  sent:%d/%d  pending:%d  failed:%d  blocked:%d  skipped:%d\n",
+			b.Sent, b.Total, b.Pending, b.Failed, b.Blocked, b.Skipped,
+		)
+
+		sb.WriteString("  > " + shorten(b.Text, 60) + "\n")
+	}
+
+	return "<pre>" + bot.EscapeHTML(strings.TrimRight(sb.String(), "\n")) + "</pre>"
+}
+
+// userTable — first page of the user's debts and the state of the session
+func userTable(page *model.DebtPage, ses *session.Session) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "debts: %d shown of %d, amount %d\n", len(page.Debts), page.Total, page.TotalAmount)
+
+	for _, d := range page.Debts {
+		fmt.Fprintf(&sb, "  #%d  %d  %s  %q\n", d.ID, d.Amount, auditDate(d), shorten(d.Description, 40))
+	}
+
+	sb.WriteString("session: " + sessionState(ses))
+
+	return "<pre>" + bot.EscapeHTML(sb.String()) + "</pre>"
+}
+
+// sessionsTable — one line per user with the handler and step the session is stuck on
+func sessionsTable(ids []int, sessions map[int]*session.Session) string {
+	var sb strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&sb, "user:%d  %s\n", id, sessionState(sessions[id]))
+	}
+
+	return "<pre>" + bot.EscapeHTML(strings.TrimRight(sb.String(), "\n")) + "</pre>"
+}
+
+// sessionState — handler/step of the session and its age when known
+func sessionState(ses *session.Session) string {
+	if ses == nil {
+		return "none"
+	}
+
+	state, err := manager.ExtractState(ses)
+	if err != nil {
+		return fmt.Sprintf("unknown %T", ses.State)
+	}
+
+	res := state.Handler.String() + "/" + state.Step.String()
+	if ses.UpdatedAt != nil {
+		res += "  " + time.Since(*ses.UpdatedAt).Round(time.Second).String() + " ago"
+	}
+
+	return res
+}
+
+func shorten(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n]) + "…"
+}
+
+func auditChanges(before, after *model.Debt) []string {
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil:
+		return []string{"+ " + auditDebt(after)}
+	case after == nil:
+		return []string{"- " + auditDebt(before)}
+	}
+
+	var res []string
+	if before.Description != after.Description {
+		res = append(res, fmt.Sprintf("description: %q → %q", before.Description, after.Description))
+	}
+
+	if before.Amount != after.Amount {
+		res = append(res, fmt.Sprintf("amount: %d → %d", before.Amount, after.Amount))
+	}
+
+	if auditDate(before) != auditDate(after) {
+		res = append(res, fmt.Sprintf("return date: %s → %s", auditDate(before), auditDate(after)))
+	}
+
+	return res
+}
+
+func auditDebt(d *model.Debt) string {
+	return fmt.Sprintf("%q %d %s", d.Description, d.Amount, auditDate(d))
+}
+
+func auditDate(d *model.Debt) string {
+	if d.ReturnDate == nil {
+		return manager.AuditNoDate
+	}
+
+	return d.ReturnDate.Format("02.01.2006")
+}
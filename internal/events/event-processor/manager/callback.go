@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxCallBackLen — telegram limit for callback_data in bytes
+const MaxCallBackLen = 64
+
+// callBackVersion — first byte of every encoded callback, bump it when the layout changes
+const callBackVersion byte = 1
+
+var (
+	ErrCallBackTooLong        = errors.New("callback data exceeds telegram limit")
+	ErrCallBackVersion        = errors.New("unsupported callback version")
+	ErrCallBackInvalidPayload = errors.New("invalid callback payload")
+)
+
+type CallBack struct {
+	Handler TypeHandler `json:"h"`
+	Step    Step        `json:"s"`
+	Data    string      `json:"d"`
+}
+
+// CreateCallBack — serializes callback data into versioned binary, encoded with url-safe base64:
+// [version][uvarint handler][uvarint step][data...]
+func CreateCallBack(handler TypeHandler, step Step, data string) (string, error) {
+	if handler < 0 || step < 0 {
+		return "", fmt.Errorf("failed to create callback: %w: negative handler or step", ErrCallBackInvalidPayload)
+	}
+
+	buf := make([]byte, 0, 1+2*binary.MaxVarintLen64+len(data))
+	buf = append(buf, callBackVersion)
+	buf = binary.AppendUvarint(buf, uint64(handler))
+	buf = binary.AppendUvarint(buf, uint64(step))
+	buf = append(buf, data...)
+
+	res := base64.RawURLEncoding.EncodeToString(buf)
+	if len(res) > MaxCallBackLen {
+		return "", fmt.Errorf(
+			"failed to create callback h:%d s:%d: %w: %d/%d bytes",
+			handler, step, ErrCallBackTooLong, len(res), MaxCallBackLen,
+		)
+	}
+
+	return res, nil
+}
+
+// ParseCallBack — deserializes callback data, buttons sent before the binary codec are still JSON
+func ParseCallBack(data string) (*CallBack, error) {
+	if strings.HasPrefix(data, "{") {
+		return parseLegacyCallBack(data)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid callback data: %w, raw: %s", err, data)
+	}
+
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("invalid callback data: %w, raw: %s", ErrCallBackInvalidPayload, data)
+	}
+
+	if raw[0] != callBackVersion {
+		return nil, fmt.Errorf("invalid callback data: %w: %d", ErrCallBackVersion, raw[0])
+	}
+	raw = raw[1:]
+
+	handler, n := binary.Uvarint(raw)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid callback data: %w: handler, raw: %s", ErrCallBackInvalidPayload, data)
+	}
+	raw = raw[n:]
+
+	step, n := binary.Uvarint(raw)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid callback data: %w: step, raw: %s", ErrCallBackInvalidPayload, data)
+	}
+	raw = raw[n:]
+
+	return &CallBack{
+		Handler: TypeHandler(handler),
+		Step:    Step(step),
+		Data:    string(raw),
+	}, nil
+}
+
+func parseLegacyCallBack(data string) (*CallBack, error) {
+	var cb CallBack
+	if err := json.Unmarshal([]byte(data), &cb); err != nil {
+		return nil, fmt.Errorf("invalid callback data: %w, raw: %s", err, data)
+	}
+	return &cb, nil
+}
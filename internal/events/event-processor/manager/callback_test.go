@@ -0,0 +1,128 @@
+package manager_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"drillCore/internal/events/event-processor/manager"
+)
+
+// maxData — longest data that still fits telegram limit after the version, handler and step bytes
+func maxData(header int) string {
+	return strings.Repeat("x", base64.RawURLEncoding.DecodedLen(manager.MaxCallBackLen)-header)
+}
+
+func TestCallBackRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler manager.TypeHandler
+		step    manager.Step
+		data    string
+	}{
+		{"empty data", manager.DebtHandler, manager.StepList, ""},
+		{"plain data", manager.DateHandler, manager.StepDay, "2025-01-31"},
+		{"unicode data", manager.DebtHandler, manager.StepSelect, "бур|42"},
+		{"multi-byte handler", 128, manager.StepStart, "42"},
+		{"multi-byte step", manager.DebtHandler, 300, "42"},
+		{"multi-byte both", 1 << 20, 1 << 14, ""},
+		{"data at limit", manager.DebtHandler, manager.StepList, maxData(3)},
+		{"data at limit with multi-byte values", 128, 128, maxData(5)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := manager.CreateCallBack(tc.handler, tc.step, tc.data)
+			if err != nil {
+				t.Fatalf("CreateCallBack: %v", err)
+			}
+			if len(data) > manager.MaxCallBackLen {
+				t.Fatalf("callback is %d bytes, limit is %d", len(data), manager.MaxCallBackLen)
+			}
+
+			cb, err := manager.ParseCallBack(data)
+			if err != nil {
+				t.Fatalf("ParseCallBack(%q): %v", data, err)
+			}
+
+			if cb.Handler != tc.handler || cb.Step != tc.step || cb.Data != tc.data {
+				t.Fatalf("got %+v, want h:%d s:%d d:%q", cb, tc.handler, tc.step, tc.data)
+			}
+		})
+	}
+}
+
+func TestCreateCallBackTooLong(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler manager.TypeHandler
+		step    manager.Step
+		data    string
+	}{
+		// unpadded base64 of 49 bytes is 66 chars, 65 is never produced
+		{"one byte over", manager.DebtHandler, manager.StepList, maxData(3) + "x"},
+		{"multi-byte values push data over", 128, 128, maxData(3)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := manager.CreateCallBack(tc.handler, tc.step, tc.data); !errors.Is(err, manager.ErrCallBackTooLong) {
+				t.Fatalf("got %v, want ErrCallBackTooLong", err)
+			}
+		})
+	}
+}
+
+func TestCreateCallBackNegative(t *testing.T) {
+	if _, err := manager.CreateCallBack(-1, manager.StepList, ""); !errors.Is(err, manager.ErrCallBackInvalidPayload) {
+		t.Fatalf("negative handler: got %v, want ErrCallBackInvalidPayload", err)
+	}
+	if _, err := manager.CreateCallBack(manager.DebtHandler, -1, ""); !errors.Is(err, manager.ErrCallBackInvalidPayload) {
+		t.Fatalf("negative step: got %v, want ErrCallBackInvalidPayload", err)
+	}
+}
+
+func TestParseLegacyCallBack(t *testing.T) {
+	cb, err := manager.ParseCallBack(`{"h":4,"s":2,"d":"42"}`)
+	if err != nil {
+		t.Fatalf("ParseCallBack: %v", err)
+	}
+	if cb.Handler != manager.DebtHandler || cb.Step != manager.StepList || cb.Data != "42" {
+		t.Fatalf("got %+v", cb)
+	}
+
+	if _, err := manager.ParseCallBack(`{"h":4,`); err == nil {
+		t.Fatal("broken legacy JSON parsed without error")
+	}
+}
+
+func TestParseCallBackInvalid(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+
+	cases := []struct {
+		name string
+		data string
+		want error
+	}{
+		{"empty", "", manager.ErrCallBackInvalidPayload},
+		{"wrong version", enc([]byte{2, 4, 2}), manager.ErrCallBackVersion},
+		{"zero version", enc([]byte{0, 4, 2}), manager.ErrCallBackVersion},
+		{"version only", enc([]byte{1}), manager.ErrCallBackInvalidPayload},
+		{"no step", enc([]byte{1, 4}), manager.ErrCallBackInvalidPayload},
+		{"truncated handler varint", enc([]byte{1, 0x80}), manager.ErrCallBackInvalidPayload},
+		{"truncated step varint", enc([]byte{1, 4, 0x80}), manager.ErrCallBackInvalidPayload},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := manager.ParseCallBack(tc.data); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	if _, err := manager.ParseCallBack("not base64!"); err == nil {
+		t.Fatal("invalid base64 parsed without error")
+	}
+}
@@ -2,18 +2,20 @@ package debt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"drillCore/internal/bot"
 	"drillCore/internal/events"
 	"drillCore/internal/events/event-processor/manager"
 	"drillCore/internal/model"
 	"drillCore/internal/session"
+	debtStorage "drillCore/internal/storage/debt"
 
 	"go.uber.org/zap"
 )
@@ -26,29 +28,39 @@ type SessionManager interface {
 
 type Storage interface {
 	Save(ctx context.Context, debt *model.Debt) (int64, error)
-	Debts(ctx context.Context, userID int64) ([]*model.Debt, error)
-	Update(ctx context.Context, debt *model.Debt) error
-	Delete(ctx context.Context, id int64) error
-	Debt(ctx context.Context, id int64) (*model.Debt, error)
+	DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error)
+	SearchDebts(ctx context.Context, userID int64, query string, cursor, limit int) (*model.DebtPage, error)
+	ListSettings(ctx context.Context, userID int64) (*model.ListSettings, error)
+	SaveListSettings(ctx context.Context, userID int64, settings *model.ListSettings) error
+	Update(ctx context.Context, userID int64, debt *model.Debt) error
+	Delete(ctx context.Context, userID int64, debt *model.Debt) (int64, error)
+	Pay(ctx context.Context, userID int64, debt *model.Debt) (int64, error)
+	Debt(ctx context.Context, userID, id int64) (*model.Debt, error)
+	Undo(ctx context.Context, userID, eventID int64, window time.Duration) (*model.Debt, error)
 }
 
+const pageSize = 10
+
 type Handler struct {
 	tg      *bot.Client
 	sesMng  SessionManager
 	storage Storage
 	logger  *zap.SugaredLogger
 
+	undoWindow time.Duration
+
 	menuKeyBoard     bot.ReplyMarkup
 	cancelKeyBoard   bot.ReplyMarkup
 	editMenuKeyBoard bot.ReplyMarkup
 }
 
-func New(tg *bot.Client, sm SessionManager, storage Storage, logger *zap.SugaredLogger) *Handler {
+func New(tg *bot.Client, sm SessionManager, storage Storage, undoWindow time.Duration, logger *zap.SugaredLogger) *Handler {
 	h := &Handler{
-		tg:      tg,
-		sesMng:  sm,
-		storage: storage,
-		logger:  logger,
+		tg:         tg,
+		sesMng:     sm,
+		storage:    storage,
+		logger:     logger,
+		undoWindow: undoWindow,
 	}
 
 	menuKeyboard, err := h.menuKeyboard()
@@ -133,6 +145,9 @@ func (h *Handler) handleMessage(ctx context.Context, e *events.Event) error {
 	case manager.StepEditAmount:
 		return h.editAmount(ctx, e, ses, state)
 
+	case manager.StepSearch:
+		return h.search(ctx, e, ses, state)
+
 	default:
 		h.logger.Errorf("failed to handle event: %v for user %d", e, e.Meta.ChatID)
 
@@ -155,7 +170,13 @@ func (h *Handler) handleCallBack(ctx context.Context, cb *manager.CallBack, meta
 		return h.debtStart(ctx, meta.ChatID, meta.UserID)
 
 	case manager.StepList:
-		return h.list(ctx, meta.ChatID, meta.UserID)
+		return h.list(ctx, meta.ChatID, meta.UserID, cb.Data)
+
+	case manager.StepListSort:
+		return h.listSort(ctx, meta.ChatID, meta.UserID, cb.Data)
+
+	case manager.StepListFilter:
+		return h.listFilter(ctx, meta.ChatID, meta.UserID, cb.Data)
 
 	case manager.StepAddStart:
 		return h.addStart(ctx, meta.ChatID, meta.UserID)
@@ -181,6 +202,12 @@ func (h *Handler) handleCallBack(ctx context.Context, cb *manager.CallBack, meta
 	case manager.StepSelect:
 		return h.selectDebt(ctx, meta.ChatID, meta.UserID, cb.Data)
 
+	case manager.StepSelectPage:
+		return h.selectPage(ctx, meta.ChatID, meta.UserID, cb.Data)
+
+	case manager.StepSearchStart:
+		return h.searchStart(ctx, meta.ChatID, meta.UserID)
+
 	case manager.StepEditStart:
 		return h.beforeSelect(ctx, meta.ChatID, meta.UserID, manager.DebtHandler, manager.StepEditStart, manager.DebtHandler, manager.StepEditMenu, manager.MsgEditStart)
 
@@ -211,6 +238,9 @@ func (h *Handler) handleCallBack(ctx context.Context, cb *manager.CallBack, meta
 	case manager.StepPayFinish:
 		return h.payFinish(ctx, meta.ChatID, meta.UserID)
 
+	case manager.StepUndo:
+		return h.undo(ctx, meta.ChatID, meta.UserID, cb.Data)
+
 	default:
 		h.logger.Errorf("failed to handle call back: %v for user %d", cb, meta.ChatID)
 
@@ -252,10 +282,11 @@ func (h *Handler) addStart(ctx context.Context, chatID, userID int) error {
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		manager.MsgAddDescription,
+		bot.NewForceReply(manager.DescriptionPlaceholder),
 	)
 }
 
@@ -299,13 +330,15 @@ func (h *Handler) addDescription(ctx context.Context, e *events.Event, ses *sess
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		e.Meta.ChatID,
 		fmt.Sprintf(
 			manager.MsgAddAmount,
-			strings.ToUpper(e.Text),
+			bot.EscapeHTML(strings.ToUpper(e.Text)),
 		),
+		bot.NewForceReply(manager.AmountPlaceholder),
+		bot.WithHTML(),
 	)
 }
 
@@ -366,7 +399,7 @@ func (h *Handler) addAmount(ctx context.Context, e *events.Event, ses *session.S
 		)
 	}
 
-	msg := fmt.Sprintf(manager.MsgAddDate, formatMoney(amount)) + manager.MsgStartDateFlow
+	msg := fmt.Sprintf(manager.MsgAddDate, manager.FormatMoney(amount)) + manager.MsgStartDateFlow
 
 	return h.tg.SendMessageWithKeyboard(
 		ctx,
@@ -399,10 +432,11 @@ func (h *Handler) addDescriptionRedirect(ctx context.Context, chatID, userID int
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		manager.MsgAddDescription,
+		bot.NewForceReply(manager.DescriptionPlaceholder),
 	)
 }
 
@@ -429,13 +463,15 @@ func (h *Handler) addAmountRedirect(ctx context.Context, chatID, userID int) err
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		fmt.Sprintf(
 			manager.MsgAddAmount,
-			strings.ToUpper(state.TempDebt.Description),
+			bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
 		),
+		bot.NewForceReply(manager.AmountPlaceholder),
+		bot.WithHTML(),
 	)
 }
 
@@ -475,11 +511,12 @@ func (h *Handler) addFinish(ctx context.Context, chatID, userID int) error {
 		chatID,
 		fmt.Sprintf(
 			manager.MsgSavedDebt,
-			strings.ToUpper(state.TempDebt.Description),
-			formatMoney(state.TempDebt.Amount),
+			bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
+			manager.FormatMoney(state.TempDebt.Amount),
 			state.TempDebt.ReturnDate.Format("02.01.2006"),
 		),
 		h.menuKeyBoard,
+		bot.WithHTML(),
 	)
 }
 
@@ -509,14 +546,30 @@ func (h *Handler) deleteConfirm(ctx context.Context, chatID, userID int) error {
 }
 
 func (h *Handler) deleteFinish(ctx context.Context, chatID, userID int) error {
-	defer h.cleanupSession(ctx, userID)
-
-	_, state, err := h.getSession(ctx, userID, chatID)
+	ses, state, err := h.getSession(ctx, userID, chatID)
 	if err != nil {
 		return fmt.Errorf("failed to delete finish for userID:%d :%v", userID, err)
 	}
 
-	if err := h.storage.Delete(ctx, state.TempDebt.ID); err != nil {
+	eventID, err := h.storage.Delete(ctx, int64(userID), state.TempDebt)
+	if errors.Is(err, debtStorage.ErrDebtConflict) {
+		kb, err := h.confirmKeyboard(manager.StepDeleteFinish)
+		if err != nil {
+			h.cleanupSession(ctx, userID)
+
+			return h.tg.SendMessage(
+				ctx,
+				chatID,
+				manager.FailedToCreateKeyboard,
+			)
+		}
+
+		return h.reloadDebt(ctx, chatID, userID, ses, state, manager.StepDeleteConfirm, kb)
+	}
+
+	h.cleanupSession(ctx, userID)
+
+	if err != nil {
 		h.logger.Errorf("failed to delete debt %d: %v", state.TempDebt.ID, err)
 
 		return h.tg.SendMessageWithKeyboard(
@@ -532,10 +585,11 @@ func (h *Handler) deleteFinish(ctx context.Context, chatID, userID int) error {
 		chatID,
 		fmt.Sprintf(
 			manager.MsgDeleteDebt,
-			strings.ToUpper(state.TempDebt.Description),
-			formatMoney(state.TempDebt.Amount),
+			bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
+			manager.FormatMoney(state.TempDebt.Amount),
 		),
-		h.menuKeyBoard,
+		h.undoKeyboard(eventID),
+		bot.WithHTML(),
 	)
 }
 
@@ -560,10 +614,11 @@ func (h *Handler) enterPayment(ctx context.Context, chatID, userID int) error {
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		manager.MsgEnterAmount,
+		bot.NewForceReply(manager.AmountPlaceholder),
 	)
 }
 
@@ -593,7 +648,7 @@ func (h *Handler) payAmount(ctx context.Context, e *events.Event, ses *session.S
 			e.Meta.ChatID,
 			fmt.Sprintf(
 				manager.MsgToLargeAmount,
-				formatMoney(state.TempDebt.Amount),
+				manager.FormatMoney(state.TempDebt.Amount),
 			),
 			h.cancelKeyBoard,
 		)
@@ -616,10 +671,10 @@ func (h *Handler) payAmount(ctx context.Context, e *events.Event, ses *session.S
 
 	confirmMsg := fmt.Sprintf(
 		manager.MsgPayConfirm,
-		strings.ToUpper(state.TempDebt.Description),
-		formatMoney(state.TempDebt.Amount),
-		formatMoney(amount),
-		formatMoney(newAmount),
+		bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
+		manager.FormatMoney(state.TempDebt.Amount),
+		manager.FormatMoney(amount),
+		manager.FormatMoney(newAmount),
 	)
 
 	confirmKb, err := h.confirmKeyboard(manager.StepPayFinish)
@@ -637,20 +692,28 @@ func (h *Handler) payAmount(ctx context.Context, e *events.Event, ses *session.S
 		e.Meta.ChatID,
 		confirmMsg,
 		confirmKb,
+		bot.WithHTML(),
 	)
 }
 
 func (h *Handler) payFinish(ctx context.Context, chatID, userID int) error {
-	h.cleanupSession(ctx, userID)
-
-	_, state, err := h.getSession(ctx, userID, chatID)
+	ses, state, err := h.getSession(ctx, userID, chatID)
 	if err != nil {
 		return fmt.Errorf("failed to finish pay for userID:%d :%v", userID, err)
 	}
 
+	eventID, err := h.storage.Pay(ctx, int64(userID), state.TempDebt)
+
+	// the payment was calculated from a stale amount, ask for it again
+	if errors.Is(err, debtStorage.ErrDebtConflict) {
+		return h.reloadDebt(ctx, chatID, userID, ses, state, manager.StepPayAmount, bot.NewForceReply(manager.AmountPlaceholder))
+	}
+
+	h.cleanupSession(ctx, userID)
+
 	switch state.TempDebt.Amount {
 	case 0:
-		if err := h.storage.Delete(ctx, state.TempDebt.ID); err != nil {
+		if err != nil {
 			h.logger.Errorf("failed to delete debt %d: %v", state.TempDebt.ID, err)
 
 			return h.tg.SendMessageWithKeyboard(
@@ -666,13 +729,14 @@ func (h *Handler) payFinish(ctx context.Context, chatID, userID int) error {
 			chatID,
 			fmt.Sprintf(
 				manager.MsgPayToDelete,
-				strings.ToUpper(state.TempDebt.Description),
+				bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
 			),
-			h.menuKeyBoard,
+			h.undoKeyboard(eventID),
+			bot.WithHTML(),
 		)
 
 	default:
-		if err := h.storage.Update(ctx, state.TempDebt); err != nil {
+		if err != nil {
 			h.logger.Errorf("failed to update debt %d: %v", state.TempDebt.ID, err)
 
 			return h.tg.SendMessageWithKeyboard(
@@ -688,10 +752,11 @@ func (h *Handler) payFinish(ctx context.Context, chatID, userID int) error {
 			chatID,
 			fmt.Sprintf(
 				manager.MsgPayToUpdate,
-				strings.ToUpper(state.TempDebt.Description),
-				formatMoney(state.TempDebt.Amount),
+				bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
+				manager.FormatMoney(state.TempDebt.Amount),
 			),
-			h.menuKeyBoard,
+			h.undoKeyboard(eventID),
+			bot.WithHTML(),
 		)
 	}
 }
@@ -731,10 +796,11 @@ func (h *Handler) enterAmount(ctx context.Context, chatID, userID int) error {
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		manager.MsgEnterAmount,
+		bot.NewForceReply(manager.AmountPlaceholder),
 	)
 }
 
@@ -777,7 +843,7 @@ func (h *Handler) editAmount(ctx context.Context, e *events.Event, ses *session.
 		e.Meta.ChatID,
 		fmt.Sprintf(
 			manager.MsgEditAmount,
-			formatMoney(amount),
+			manager.FormatMoney(amount),
 		),
 		h.editMenuKeyBoard,
 	)
@@ -804,10 +870,11 @@ func (h *Handler) enterDescription(ctx context.Context, chatID, userID int) erro
 		)
 	}
 
-	return h.tg.SendMessage(
+	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		manager.MsgEnterDescription,
+		bot.NewForceReply(manager.DescriptionPlaceholder),
 	)
 }
 
@@ -852,9 +919,10 @@ func (h *Handler) editDescription(ctx context.Context, e *events.Event, ses *ses
 		e.Meta.ChatID,
 		fmt.Sprintf(
 			manager.MsgEditDescription,
-			strings.ToUpper(e.Text),
+			bot.EscapeHTML(strings.ToUpper(e.Text)),
 		),
 		h.editMenuKeyBoard,
+		bot.WithHTML(),
 	)
 }
 
@@ -951,14 +1019,18 @@ func (h *Handler) editDate(ctx context.Context, chatID, userID int) error {
 }
 
 func (h *Handler) editFinish(ctx context.Context, chatID, userID int) error {
-	defer h.cleanupSession(ctx, userID)
-
-	_, state, err := h.getSession(ctx, userID, chatID)
+	ses, state, err := h.getSession(ctx, userID, chatID)
 	if err != nil {
 		return fmt.Errorf("failed to finish edit for userID:%d :%v", userID, err)
 	}
 
-	err = h.storage.Update(ctx, state.TempDebt)
+	err = h.storage.Update(ctx, int64(userID), state.TempDebt)
+	if errors.Is(err, debtStorage.ErrDebtConflict) {
+		return h.reloadDebt(ctx, chatID, userID, ses, state, manager.StepEditMenu, h.editMenuKeyBoard)
+	}
+
+	h.cleanupSession(ctx, userID)
+
 	if err != nil {
 		h.logger.Errorf("failed to save debt for user:%d : %v", userID, err)
 
@@ -975,17 +1047,18 @@ func (h *Handler) editFinish(ctx context.Context, chatID, userID int) error {
 		chatID,
 		fmt.Sprintf(
 			manager.MsgFinishEdit,
-			strings.ToUpper(state.TempDebt.Description),
-			formatMoney(state.TempDebt.Amount),
+			bot.EscapeHTML(strings.ToUpper(state.TempDebt.Description)),
+			manager.FormatMoney(state.TempDebt.Amount),
 			debtStatus(state.TempDebt),
 		),
 		h.menuKeyBoard,
+		bot.WithHTML(),
 	)
 }
 
 func (h *Handler) beforeSelect(ctx context.Context, chatID, userID int, backH manager.TypeHandler, backS manager.Step,
 	nextH manager.TypeHandler, nextS manager.Step, msg string) error {
-	debts, err := h.storage.Debts(ctx, int64(userID))
+	page, err := h.storage.DebtsPage(ctx, int64(userID), 0, pageSize, nil)
 	if err != nil {
 		h.logger.Errorf("failed to get debts: %v", err)
 
@@ -997,7 +1070,7 @@ func (h *Handler) beforeSelect(ctx context.Context, chatID, userID int, backH ma
 		)
 	}
 
-	if len(debts) == 0 {
+	if page.Total == 0 {
 		return h.tg.SendMessageWithKeyboard(
 			ctx,
 			chatID,
@@ -1029,10 +1102,118 @@ func (h *Handler) beforeSelect(ctx context.Context, chatID, userID int, backH ma
 		)
 	}
 
-	sDebts := sortDebts(debts)
+	return h.sendSelectPage(ctx, chatID, userID, page, "", msg)
+}
+
+func (h *Handler) selectPage(ctx context.Context, chatID, userID int, data string) error {
+	_, state, err := h.getSession(ctx, userID, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to select page for userID:%d :%v", userID, err)
+	}
 
-	kb, err := h.selectKeyboard(sDebts)
+	page, err := h.debtsPage(ctx, userID, state.Query, parseCursor(data), nil)
 	if err != nil {
+		h.logger.Errorf("failed to get debts page for user: %d : %v", userID, err)
+
+		h.cleanupSession(ctx, userID)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.MsgFailedToGetDebt,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.sendSelectPage(ctx, chatID, userID, page, state.Query, manager.MsgSelectPage)
+}
+
+func (h *Handler) searchStart(ctx context.Context, chatID, userID int) error {
+	ses, state, err := h.getSession(ctx, userID, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to start search for userID:%d :%v", userID, err)
+	}
+
+	state.Step = manager.StepSearch
+	ses.State = state
+
+	err = h.sesMng.Set(ctx, userID, ses)
+	if err != nil {
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.MsgFailedToSetSession,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.tg.SendMessageWithKeyboard(
+		ctx,
+		chatID,
+		manager.MsgEnterSearch,
+		bot.NewForceReply(manager.SearchPlaceholder),
+	)
+}
+
+func (h *Handler) search(ctx context.Context, e *events.Event, ses *session.Session, state *manager.State) error {
+	query := strings.TrimSpace(e.Text)
+	if query == "" {
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			e.Meta.ChatID,
+			manager.MsgInvalidSearchEmpty,
+			h.cancelKeyBoard,
+		)
+	}
+
+	if n := utf8.RuneCountInString(query); n > 100 {
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			e.Meta.ChatID,
+			fmt.Sprintf(
+				manager.MsgInvalidSearchLength,
+				n,
+			),
+			h.cancelKeyBoard,
+		)
+	}
+
+	state.Query = query
+	state.Step = manager.StepSelect
+	ses.State = state
+
+	err := h.sesMng.Set(ctx, e.Meta.UserID, ses)
+	if err != nil {
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			e.Meta.ChatID,
+			manager.MsgFailedToSetSession,
+			h.menuKeyBoard,
+		)
+	}
+
+	page, err := h.storage.SearchDebts(ctx, int64(e.Meta.UserID), query, 0, pageSize)
+	if err != nil {
+		h.logger.Errorf("failed to search debts for user: %d : %v", e.Meta.UserID, err)
+
+		h.cleanupSession(ctx, e.Meta.UserID)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			e.Meta.ChatID,
+			manager.MsgFailedToGetDebt,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.sendSelectPage(ctx, e.Meta.ChatID, e.Meta.UserID, page, query, manager.MsgSelectPage)
+}
+
+func (h *Handler) sendSelectPage(ctx context.Context, chatID, userID int, page *model.DebtPage, query, msg string) error {
+	kb, err := h.selectKeyboard(page)
+	if err != nil {
+		h.logger.Errorf("failed to create select keyboard for user %d: %v", userID, err)
+
 		h.cleanupSession(ctx, userID)
 
 		return h.tg.SendMessage(
@@ -1042,14 +1223,56 @@ func (h *Handler) beforeSelect(ctx context.Context, chatID, userID int, backH ma
 		)
 	}
 
+	if page.Total == 0 {
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			fmt.Sprintf(
+				manager.MsgSearchEmpty,
+				bot.EscapeHTML(query),
+			),
+			kb,
+			bot.WithHTML(),
+		)
+	}
+
+	text := msg + fmt.Sprintf(manager.MsgPageFormat, page.Number(), page.Pages(), page.Total)
+	if query != "" {
+		text += fmt.Sprintf(manager.MsgSearchQueryFormat, bot.EscapeHTML(query))
+	}
+
 	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
-		msg,
+		text,
 		kb,
+		bot.WithHTML(),
 	)
 }
 
+// debtsPage — page of debts filtered by query (or sorted and filtered by settings), falls back
+// to the first page when the cursor went out of range (e.g. debts were deleted since the keyboard was sent)
+func (h *Handler) debtsPage(ctx context.Context, userID int, query string, cursor int, settings *model.ListSettings) (*model.DebtPage, error) {
+	fetch := func(cursor int) (*model.DebtPage, error) {
+		if query != "" {
+			return h.storage.SearchDebts(ctx, int64(userID), query, cursor, pageSize)
+		}
+
+		return h.storage.DebtsPage(ctx, int64(userID), cursor, pageSize, settings)
+	}
+
+	page, err := fetch(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page.Debts) == 0 && page.Cursor > 0 {
+		return fetch(0)
+	}
+
+	return page, nil
+}
+
 func (h *Handler) selectDebt(ctx context.Context, chatID, userID int, data string) error {
 	ses, state, err := h.getSession(ctx, userID, chatID)
 	if err != nil {
@@ -1070,7 +1293,7 @@ func (h *Handler) selectDebt(ctx context.Context, chatID, userID int, data strin
 		)
 	}
 
-	debt, err := h.storage.Debt(ctx, debtID)
+	debt, err := h.storage.Debt(ctx, int64(userID), debtID)
 	if err != nil {
 		h.logger.Errorf("failed to get debt for user: %d :%v", userID, err)
 
@@ -1084,19 +1307,75 @@ func (h *Handler) selectDebt(ctx context.Context, chatID, userID int, data strin
 		)
 	}
 
-	if debt.UserID != int64(userID) {
-		h.logger.Errorf("debtID:%d relate to user:%d, request user:%d", debt.ID, debt.UserID, userID)
+	state.TempDebt = debt
+	ses.State = state
 
+	err = h.sesMng.Set(ctx, userID, ses)
+	if err != nil {
+		h.logger.Errorf("failed to set state for user %d", userID)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.MsgFailedToSetSession,
+			h.menuKeyBoard,
+		)
+	}
+
+	redirectKb, err := h.redirectKeyboard(state.BackHandler, state.BackStep, state.NextHandler, state.NextStep)
+	if err != nil {
 		h.cleanupSession(ctx, userID)
 
+		return h.tg.SendMessage(
+			ctx,
+			chatID,
+			manager.FailedToCreateKeyboard,
+		)
+	}
+
+	return h.tg.SendMessageWithKeyboard(
+		ctx,
+		chatID,
+		fmt.Sprintf(
+			manager.MsgDebtSelected,
+			bot.EscapeHTML(debt.Description),
+			manager.FormatMoney(debt.Amount),
+			debtStatus(debt),
+		),
+		redirectKb,
+		bot.WithHTML(),
+	)
+}
+
+// reloadDebt — the selected debt was changed by someone else since it was read: the fresh one replaces
+// TempDebt in the session, so the user repeats the step with the actual values
+func (h *Handler) reloadDebt(ctx context.Context, chatID, userID int, ses *session.Session, state *manager.State,
+	step manager.Step, kb bot.ReplyMarkup) error {
+	debt, err := h.storage.Debt(ctx, int64(userID), state.TempDebt.ID)
+	if err != nil {
+		h.cleanupSession(ctx, userID)
+
+		if errors.Is(err, debtStorage.ErrDebtNotFound) {
+			return h.tg.SendMessageWithKeyboard(
+				ctx,
+				chatID,
+				manager.MsgDebtDeletedMeanwhile,
+				h.menuKeyBoard,
+			)
+		}
+
+		h.logger.Errorf("failed to reload debt %d for user %d: %v", state.TempDebt.ID, userID, err)
+
 		return h.tg.SendMessageWithKeyboard(
 			ctx,
 			chatID,
-			manager.MsgUserIdNotEqualDebtId,
+			manager.MsgFailedToGetDebt,
 			h.menuKeyBoard,
 		)
 	}
 
+	state.Handler = manager.DebtHandler
+	state.Step = step
 	state.TempDebt = debt
 	ses.State = state
 
@@ -1112,14 +1391,51 @@ func (h *Handler) selectDebt(ctx context.Context, chatID, userID int, data strin
 		)
 	}
 
-	redirectKb, err := h.redirectKeyboard(state.BackHandler, state.BackStep, state.NextHandler, state.NextStep)
+	return h.tg.SendMessageWithKeyboard(
+		ctx,
+		chatID,
+		fmt.Sprintf(
+			manager.MsgDebtChangedMeanwhile,
+			bot.EscapeHTML(strings.ToUpper(debt.Description)),
+			manager.FormatMoney(debt.Amount),
+			debtStatus(debt),
+		),
+		kb,
+		bot.WithHTML(),
+	)
+}
+
+// undo — reverts the delete or payment the undo button was attached to
+func (h *Handler) undo(ctx context.Context, chatID, userID int, data string) error {
+	h.cleanupSession(ctx, userID)
+
+	eventID, err := strconv.ParseInt(data, 10, 64)
 	if err != nil {
-		h.cleanupSession(ctx, userID)
+		h.logger.Errorf("failed to extract audit event id from undo %s", data)
 
-		return h.tg.SendMessage(
+		return h.tg.SendMessageWithKeyboard(
 			ctx,
 			chatID,
-			manager.FailedToCreateKeyboard,
+			manager.MsgFailedToExtractDebtId,
+			h.menuKeyBoard,
+		)
+	}
+
+	debt, err := h.storage.Undo(ctx, int64(userID), eventID, h.undoWindow)
+	if err != nil {
+		msg := manager.MsgFailedToUndo
+		if errors.Is(err, debtStorage.ErrNothingToUndo) || errors.Is(err, debtStorage.ErrDebtConflict) ||
+			errors.Is(err, debtStorage.ErrDebtNotFound) {
+			msg = manager.MsgNothingToUndo
+		} else {
+			h.logger.Errorf("failed to undo audit event %d for user %d: %v", eventID, userID, err)
+		}
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			msg,
+			h.menuKeyBoard,
 		)
 	}
 
@@ -1127,12 +1443,13 @@ func (h *Handler) selectDebt(ctx context.Context, chatID, userID int, data strin
 		ctx,
 		chatID,
 		fmt.Sprintf(
-			manager.MsgDebtSelected,
-			debt.Description,
-			formatMoney(debt.Amount),
+			manager.MsgUndoDone,
+			bot.EscapeHTML(strings.ToUpper(debt.Description)),
+			manager.FormatMoney(debt.Amount),
 			debtStatus(debt),
 		),
-		redirectKb,
+		h.menuKeyBoard,
+		bot.WithHTML(),
 	)
 }
 
@@ -1147,8 +1464,93 @@ func (h *Handler) debtStart(ctx context.Context, chatID int, userID int) error {
 	)
 }
 
-func (h *Handler) list(ctx context.Context, chatID, userID int) error {
-	debts, err := h.storage.Debts(ctx, int64(userID))
+func (h *Handler) list(ctx context.Context, chatID, userID int, data string) error {
+	if settings, ok := manager.ListSettingsFromContext(ctx); ok {
+		return h.renderList(ctx, chatID, userID, parseCursor(data), settings)
+	}
+
+	settings, err := h.storage.ListSettings(ctx, int64(userID))
+	if err != nil {
+		h.logger.Errorf("failed to get list settings for user: %d : %v", userID, err)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.FailedToGetDebts,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.renderList(ctx, chatID, userID, parseCursor(data), settings)
+}
+
+func (h *Handler) listSort(ctx context.Context, chatID, userID int, data string) error {
+	sort, err := strconv.Atoi(data)
+	if err != nil || sort < int(model.SortByDueDate) || sort > int(model.SortByName) {
+		h.logger.Errorf("invalid list sort %q for user %d", data, userID)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.FailedToGetCallBack,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.updateListSettings(ctx, chatID, userID, func(s *model.ListSettings) {
+		s.Sort = model.DebtSort(sort)
+	})
+}
+
+func (h *Handler) listFilter(ctx context.Context, chatID, userID int, data string) error {
+	filter, err := strconv.Atoi(data)
+	if err != nil || filter < int(model.FilterAll) || filter > int(model.FilterNoDate) {
+		h.logger.Errorf("invalid list filter %q for user %d", data, userID)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.FailedToGetCallBack,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.updateListSettings(ctx, chatID, userID, func(s *model.ListSettings) {
+		s.Filter = model.DebtFilter(filter)
+	})
+}
+
+func (h *Handler) updateListSettings(ctx context.Context, chatID, userID int, update func(s *model.ListSettings)) error {
+	settings, err := h.storage.ListSettings(ctx, int64(userID))
+	if err != nil {
+		h.logger.Errorf("failed to get list settings for user: %d : %v", userID, err)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.FailedToGetDebts,
+			h.menuKeyBoard,
+		)
+	}
+
+	update(settings)
+
+	if err := h.storage.SaveListSettings(ctx, int64(userID), settings); err != nil {
+		h.logger.Errorf("failed to save list settings for user: %d : %v", userID, err)
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.MsgFailedToSaveListSettings,
+			h.menuKeyBoard,
+		)
+	}
+
+	return h.renderList(ctx, chatID, userID, 0, settings)
+}
+
+func (h *Handler) renderList(ctx context.Context, chatID, userID, cursor int, settings *model.ListSettings) error {
+	page, err := h.debtsPage(ctx, userID, "", cursor, settings)
 	if err != nil {
 		h.logger.Errorf("failed to get debts for user: %d : %v", userID, err)
 
@@ -1160,7 +1562,27 @@ func (h *Handler) list(ctx context.Context, chatID, userID int) error {
 		)
 	}
 
-	if len(debts) == 0 {
+	if page.Total == 0 && settings.Filter != model.FilterAll {
+		kb, err := h.listKeyboard(page, settings)
+		if err != nil {
+			h.logger.Errorf("failed to create list keyboard for user %d: %v", userID, err)
+
+			return h.tg.SendMessage(
+				ctx,
+				chatID,
+				manager.FailedToCreateKeyboard,
+			)
+		}
+
+		return h.tg.SendMessageWithKeyboard(
+			ctx,
+			chatID,
+			manager.MsgListFilterEmpty,
+			kb,
+		)
+	}
+
+	if page.Total == 0 {
 		var sb strings.Builder
 		sb.WriteString(manager.SpiralDelimiter)
 		sb.WriteString(manager.NoDebtsPhrases[rand.Intn(len(manager.NoDebtsPhrases))] + "\n\n")
@@ -1174,50 +1596,38 @@ func (h *Handler) list(ctx context.Context, chatID, userID int) error {
 		)
 	}
 
-	sortedDebts := sortDebts(debts)
-
-	total := int64(0)
-	for _, d := range sortedDebts {
-		total += d.Amount
-	}
-
 	var sb strings.Builder
 	sb.WriteString(manager.SpiralDelimiter)
 	sb.WriteString(manager.DebtTitles[rand.Intn(len(manager.DebtTitles))] + "\n\n")
 	sb.WriteString(manager.SpiralDelimiter)
 
-	for i, debt := range sortedDebts {
-		marker := manager.RageEmoji
-		if i%2 == 0 {
-			marker = manager.SpiralEmoji
-		}
-		if debt.ReturnDate != nil && debt.ReturnDate.Before(time.Now()) {
-			marker = manager.SkullEmoji
-		}
-
-		sb.WriteString(
-			fmt.Sprintf(
-				manager.ListDebtFormat,
-				marker,
-				strings.ToUpper(debt.Description),
-				formatMoney(debt.Amount),
-				debtStatus(debt),
-			),
-		)
-	}
+	sb.WriteString(debtTable(page.Debts, page.Cursor))
+	sb.WriteString(fmt.Sprintf(manager.ListPageFormat, page.Number(), page.Pages(), page.Total))
 
 	sb.WriteString(manager.SpiralDelimiter)
-	sb.WriteString(fmt.Sprintf(manager.ListTotalAmountFormat, formatMoney(total)))
+	sb.WriteString(fmt.Sprintf(manager.ListTotalAmountFormat, manager.FormatMoney(page.TotalAmount)))
 	sb.WriteString(manager.SpiralDelimiter)
 
 	sb.WriteString(manager.MotivationalPhrases[rand.Intn(len(manager.MotivationalPhrases))] + "\n\n")
 	sb.WriteString(manager.SpiralDelimiter)
 
+	kb, err := h.listKeyboard(page, settings)
+	if err != nil {
+		h.logger.Errorf("failed to create list keyboard for user %d: %v", userID, err)
+
+		return h.tg.SendMessage(
+			ctx,
+			chatID,
+			manager.FailedToCreateKeyboard,
+		)
+	}
+
 	return h.tg.SendMessageWithKeyboard(
 		ctx,
 		chatID,
 		sb.String(),
-		h.menuKeyBoard,
+		kb,
+		bot.WithHTML(),
 	)
 }
 
@@ -1259,50 +1669,13 @@ func debtStatus(debt *model.Debt) string {
 	return fmt.Sprintf(manager.ListReturnDateFormat, days)
 }
 
-func formatMoney(amount int64) string {
-	str := fmt.Sprintf("%d", amount)
-	var res []byte
-	for i, c := range str {
-		if i > 0 && (len(str)-i)%3 == 0 {
-			res = append(res, '.')
-		}
-		res = append(res, byte(c))
+func parseCursor(data string) int {
+	cursor, err := strconv.Atoi(data)
+	if err != nil || cursor < 0 {
+		return 0
 	}
-	return string(res)
-}
-
-func sortDebts(debts []*model.Debt) []*model.Debt {
-	sort.Slice(debts, func(i, j int) bool {
-		now := time.Now()
-
-		if debts[i].ReturnDate != nil && debts[j].ReturnDate != nil {
-			iOverdue := debts[i].ReturnDate.Before(now)
-			jOverdue := debts[j].ReturnDate.Before(now)
-
-			if iOverdue && jOverdue {
-				return debts[i].ReturnDate.Before(*debts[j].ReturnDate)
-			}
-
-			if iOverdue {
-				return false
-			}
-			if jOverdue {
-				return true
-			}
-
-			return debts[i].ReturnDate.Before(*debts[j].ReturnDate)
-		}
-
-		if debts[i].ReturnDate == nil {
-			return false
-		}
-		if debts[j].ReturnDate == nil {
-			return true
-		}
-		return true
-	})
 
-	return debts
+	return cursor
 }
 
 func (h *Handler) cleanupSession(ctx context.Context, userID int) {
@@ -0,0 +1,256 @@
+package debt_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/bot/fake"
+	"drillCore/internal/config"
+	"drillCore/internal/events"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/events/event-processor/manager/date"
+	"drillCore/internal/events/event-processor/manager/debt"
+	"drillCore/internal/model"
+	"drillCore/internal/session"
+	"drillCore/internal/storage/debt/memory"
+
+	"go.uber.org/zap"
+)
+
+const userID = 1
+
+// pilot — a user talking to the debt and date handlers over the fake telegram api
+type pilot struct {
+	t       *testing.T
+	srv     *fake.Server
+	mng     *manager.Manager
+	storage *memory.DebtStorage
+
+	last fake.Message
+}
+
+func newPilot(t *testing.T) *pilot {
+	t.Helper()
+
+	logger := zap.NewNop().Sugar()
+
+	srv := fake.New()
+	t.Cleanup(srv.Close)
+
+	tg := bot.New(&config.TelegramEnvs{Token: "test", BaseUrl: srv.URL()}, logger)
+	sMng := session.New()
+	storage := memory.New(logger)
+
+	mng := manager.New(
+		tg,
+		sMng,
+		logger,
+		[]manager.Middleware{manager.Settings(storage, logger, manager.DebtHandler)},
+		debt.New(tg, sMng, storage, time.Hour, logger),
+		date.New(tg, sMng, logger),
+	)
+
+	return &pilot{t: t, srv: srv, mng: mng, storage: storage}
+}
+
+// open — shows the debt menu, the starting point of every flow
+func (p *pilot) open() {
+	p.t.Helper()
+
+	cb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepStart, "")
+	if err != nil {
+		p.t.Fatalf("create callback: %v", err)
+	}
+
+	p.handle(events.Callback, cb)
+}
+
+// send — the pilot writes text, as a reply to the last message
+func (p *pilot) send(text string) {
+	p.t.Helper()
+	p.handle(events.Message, text)
+}
+
+// press — the pilot presses the button of the last message
+func (p *pilot) press(text string) {
+	p.t.Helper()
+
+	for _, row := range p.last.Markup.InlineKeyboard {
+		for _, b := range row {
+			if b.Text == text {
+				p.handle(events.Callback, b.CallbackData)
+				return
+			}
+		}
+	}
+
+	p.t.Fatalf("no button %q in the last message %q", text, p.last.Text)
+}
+
+// handle — passes the event to the manager and waits for the only reply
+func (p *pilot) handle(typ events.Type, text string) {
+	p.t.Helper()
+
+	err := p.mng.HandleEvent(p.t.Context(), &events.Event{
+		Type: typ,
+		Text: text,
+		Meta: &events.Meta{ChatID: userID, UserID: userID},
+	})
+	if err != nil {
+		p.t.Fatalf("handle %v %q: %v", typ, text, err)
+	}
+
+	select {
+	case p.last = <-p.srv.Messages():
+	default:
+		p.t.Fatalf("no reply to %v %q", typ, text)
+	}
+
+	select {
+	case m := <-p.srv.Messages():
+		p.t.Fatalf("unexpected second reply %q", m.Text)
+	default:
+	}
+}
+
+func (p *pilot) assertReply(contains ...string) {
+	p.t.Helper()
+
+	for _, s := range contains {
+		if !strings.Contains(p.last.Text, s) {
+			p.t.Fatalf("reply %q does not contain %q", p.last.Text, s)
+		}
+	}
+}
+
+func (p *pilot) save(description string, amount int64, returnDate *time.Time) *model.Debt {
+	p.t.Helper()
+
+	d := &model.Debt{UserID: userID, Description: description, Amount: amount, ReturnDate: returnDate}
+
+	id, err := p.storage.Save(p.t.Context(), d)
+	if err != nil {
+		p.t.Fatalf("save: %v", err)
+	}
+
+	d.ID = id
+	return d
+}
+
+func (p *pilot) debt(id int64) *model.Debt {
+	p.t.Helper()
+
+	d, err := p.storage.Debt(p.t.Context(), userID, id)
+	if err != nil {
+		p.t.Fatalf("debt %d: %v", id, err)
+	}
+
+	return d
+}
+
+func TestAddDebt(t *testing.T) {
+	p := newPilot(t)
+	year := time.Now().Year() + 1
+
+	p.open()
+	p.press(manager.AddDebtButton)
+	p.assertReply(manager.MsgAddDescription)
+
+	p.send("Core drill")
+	p.send("0")
+	p.assertReply(manager.MsgInvalidAmountConvertErr)
+
+	p.send("1500")
+	p.assertReply("1.500")
+
+	p.press(manager.SelectDateButton)
+	p.press(fmt.Sprintf(manager.SpiralFormat, year))
+	p.press("🌀 JAN")
+	p.press("15")
+	p.press(manager.RedirectDateButton)
+	p.assertReply("CORE DRILL", "1.500", fmt.Sprintf("15.01.%d", year))
+
+	page, err := p.storage.DebtsPage(t.Context(), userID, 0, 10, nil)
+	if err != nil {
+		t.Fatalf("debts page: %v", err)
+	}
+	if page.Total != 1 {
+		t.Fatalf("got %d debts, want 1", page.Total)
+	}
+
+	got := page.Debts[0]
+	want := time.Date(year, time.January, 15, 0, 0, 0, 0, time.UTC)
+	if got.Description != "Core drill" || got.Amount != 1500 || got.ReturnDate == nil || !got.ReturnDate.Equal(want) {
+		t.Fatalf("got debt %+v (return date %v), want core drill for 1500 due %v", got, got.ReturnDate, want)
+	}
+}
+
+func TestPayDebt(t *testing.T) {
+	p := newPilot(t)
+	d := p.save("drill", 1000, nil)
+
+	p.open()
+	p.press(manager.PayDebtButton)
+	p.press("🌀 drill - 1.000₽")
+	p.press(manager.RedirectDebtButton)
+
+	p.send("1001")
+	p.assertReply("1.000")
+
+	p.send("400")
+	p.press(manager.ConfirmButton)
+	p.assertReply("DRILL", "600")
+
+	if got := p.debt(d.ID); got.Amount != 600 {
+		t.Fatalf("amount after the payment is %d, want 600", got.Amount)
+	}
+
+	p.press(manager.UndoButton)
+	p.assertReply("1.000")
+
+	if got := p.debt(d.ID); got.Amount != 1000 {
+		t.Fatalf("amount after the undo is %d, want 1000", got.Amount)
+	}
+}
+
+func TestListDebts(t *testing.T) {
+	p := newPilot(t)
+	soon := time.Now().UTC().AddDate(0, 0, 1)
+	later := time.Now().UTC().AddDate(0, 1, 0)
+
+	p.save("small drill", 100, &soon)
+	p.save("giga drill", 5000, &later)
+
+	p.open()
+	p.press(manager.ListDebtButton)
+	p.assertReply("SMALL DRILL", "GIGA DRILL", "5.100")
+	assertOrder(t, p.last.Text, "SMALL DRILL", "GIGA DRILL")
+
+	p.press(manager.SortByAmountButton)
+	assertOrder(t, p.last.Text, "GIGA DRILL", "SMALL DRILL")
+
+	// the active sort is marked and stays for the next list
+	p.press(manager.ListDebtButton)
+	assertOrder(t, p.last.Text, "GIGA DRILL", "SMALL DRILL")
+	p.press(fmt.Sprintf(manager.ActiveOptionFormat, manager.SortByAmountButton))
+
+	settings, err := p.storage.ListSettings(t.Context(), userID)
+	if err != nil {
+		t.Fatalf("list settings: %v", err)
+	}
+	if settings.Sort != model.SortByAmount {
+		t.Fatalf("stored sort is %v, want %v", settings.Sort, model.SortByAmount)
+	}
+}
+
+func assertOrder(t *testing.T, text string, first, second string) {
+	t.Helper()
+
+	i, j := strings.Index(text, first), strings.Index(text, second)
+	if i < 0 || j < 0 || i > j {
+		t.Fatalf("want %q before %q in %q", first, second, text)
+	}
+}
@@ -62,6 +62,22 @@ func (h *Handler) menuKeyboard() (bot.ReplyMarkup, error) {
 	}), nil
 }
 
+// undoKeyboard — debt menu with the undo button on top, falls back to the plain menu.
+// The button carries the audit event of the change, so it reverts exactly that change.
+func (h *Handler) undoKeyboard(eventID int64) bot.ReplyMarkup {
+	undoCb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepUndo, strconv.FormatInt(eventID, 10))
+	if err != nil {
+		h.logger.Errorf("failed to create undo callback for audit event %d: %v", eventID, err)
+		return h.menuKeyBoard
+	}
+
+	rows := make([][]bot.InlineKeyboardButton, 0, len(h.menuKeyBoard.InlineKeyboard)+1)
+	rows = append(rows, []bot.InlineKeyboardButton{{Text: manager.UndoButton, CallbackData: undoCb}})
+	rows = append(rows, h.menuKeyBoard.InlineKeyboard...)
+
+	return bot.NewInlineKeyboard(rows)
+}
+
 func (h *Handler) editKeyboard() (bot.ReplyMarkup, error) {
 	descriptionEditCb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepEnterDescription, "")
 	if err != nil {
@@ -107,22 +123,27 @@ func (h *Handler) editKeyboard() (bot.ReplyMarkup, error) {
 	}), nil
 }
 
-func (h *Handler) selectKeyboard(debts []*model.Debt) (bot.ReplyMarkup, error) {
+func (h *Handler) selectKeyboard(page *model.DebtPage) (bot.ReplyMarkup, error) {
 	cancelCb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepStart, "")
 	if err != nil {
 		return bot.ReplyMarkup{}, err
 	}
 
+	searchCb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepSearchStart, "")
+	if err != nil {
+		return bot.ReplyMarkup{}, err
+	}
+
 	var buttons [][]bot.InlineKeyboardButton
-	for _, d := range debts {
+	for _, d := range page.Debts {
 		btnText := fmt.Sprintf("🌀 %s - %s₽",
 			truncate(d.Description, 20),
-			formatMoney(d.Amount))
+			manager.FormatMoney(d.Amount))
 
 		if d.ReturnDate != nil && d.ReturnDate.Before(time.Now()) {
 			btnText = fmt.Sprintf("💢 %s - %s₽",
 				truncate(d.Description, 20),
-				formatMoney(d.Amount))
+				manager.FormatMoney(d.Amount))
 		}
 
 		selectCb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepSelect, strconv.FormatInt(d.ID, 10))
@@ -138,13 +159,135 @@ func (h *Handler) selectKeyboard(debts []*model.Debt) (bot.ReplyMarkup, error) {
 		})
 	}
 
-	buttons = append(buttons, []bot.InlineKeyboardButton{
-		{Text: manager.CancelButton, CallbackData: cancelCb},
-	})
+	navRow, err := pageNavRow(manager.StepSelectPage, page)
+	if err != nil {
+		return bot.ReplyMarkup{}, err
+	}
+
+	if navRow != nil {
+		buttons = append(buttons, navRow)
+	}
+
+	buttons = append(buttons,
+		[]bot.InlineKeyboardButton{
+			{Text: manager.SearchButton, CallbackData: searchCb},
+		},
+		[]bot.InlineKeyboardButton{
+			{Text: manager.CancelButton, CallbackData: cancelCb},
+		},
+	)
+
+	return bot.NewInlineKeyboard(buttons), nil
+}
+
+var (
+	sortButtons = []struct {
+		sort model.DebtSort
+		text string
+	}{
+		{sort: model.SortByDueDate, text: manager.SortByDueDateButton},
+		{sort: model.SortByAmount, text: manager.SortByAmountButton},
+		{sort: model.SortByCreated, text: manager.SortByCreatedButton},
+		{sort: model.SortByName, text: manager.SortByNameButton},
+	}
+
+	filterButtons = []struct {
+		filter model.DebtFilter
+		text   string
+	}{
+		{filter: model.FilterAll, text: manager.FilterAllButton},
+		{filter: model.FilterOverdue, text: manager.FilterOverdueButton},
+		{filter: model.FilterDueThisMonth, text: manager.FilterDueThisMonthButton},
+		{filter: model.FilterNoDate, text: manager.FilterNoDateButton},
+	}
+)
+
+// listKeyboard — page navigation and sort/filter bar on top of the debt menu
+func (h *Handler) listKeyboard(page *model.DebtPage, settings *model.ListSettings) (bot.ReplyMarkup, error) {
+	navRow, err := pageNavRow(manager.StepList, page)
+	if err != nil {
+		return bot.ReplyMarkup{}, err
+	}
+
+	sortRow := make([]bot.InlineKeyboardButton, 0, len(sortButtons))
+	for _, b := range sortButtons {
+		cb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepListSort, strconv.Itoa(int(b.sort)))
+		if err != nil {
+			return bot.ReplyMarkup{}, err
+		}
+
+		text := b.text
+		if b.sort == settings.Sort {
+			text = fmt.Sprintf(manager.ActiveOptionFormat, text)
+		}
+
+		sortRow = append(sortRow, bot.InlineKeyboardButton{Text: text, CallbackData: cb})
+	}
+
+	filterRow := make([]bot.InlineKeyboardButton, 0, len(filterButtons))
+	for _, b := range filterButtons {
+		cb, err := manager.CreateCallBack(manager.DebtHandler, manager.StepListFilter, strconv.Itoa(int(b.filter)))
+		if err != nil {
+			return bot.ReplyMarkup{}, err
+		}
+
+		text := b.text
+		if b.filter == settings.Filter {
+			text = fmt.Sprintf(manager.ActiveOptionFormat, text)
+		}
+
+		filterRow = append(filterRow, bot.InlineKeyboardButton{Text: text, CallbackData: cb})
+	}
+
+	buttons := make([][]bot.InlineKeyboardButton, 0, len(h.menuKeyBoard.InlineKeyboard)+3)
+	if navRow != nil {
+		buttons = append(buttons, navRow)
+	}
+	buttons = append(buttons, sortRow, filterRow)
+	buttons = append(buttons, h.menuKeyBoard.InlineKeyboard...)
 
 	return bot.NewInlineKeyboard(buttons), nil
 }
 
+// pageNavRow — prev/next buttons leading to step with the page cursor as data, nil for a single page
+func pageNavRow(step manager.Step, page *model.DebtPage) ([]bot.InlineKeyboardButton, error) {
+	if !page.HasPrev() && !page.HasNext() {
+		return nil, nil
+	}
+
+	ignoreCb, err := manager.CreateCallBack(manager.IgnoreHandler, manager.StepIgnore, "")
+	if err != nil {
+		return nil, err
+	}
+
+	row := make([]bot.InlineKeyboardButton, 0, 3)
+
+	if page.HasPrev() {
+		prevCb, err := manager.CreateCallBack(manager.DebtHandler, step, strconv.Itoa(page.PrevCursor()))
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, bot.InlineKeyboardButton{Text: manager.PrevPageButton, CallbackData: prevCb})
+	}
+
+	row = append(row, bot.InlineKeyboardButton{
+		Text:         fmt.Sprintf(manager.PageButtonFormat, page.Number(), page.Pages()),
+		CallbackData: ignoreCb,
+	})
+
+	if page.HasNext() {
+		nextCb, err := manager.CreateCallBack(manager.DebtHandler, step, strconv.Itoa(page.NextCursor()))
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, bot.InlineKeyboardButton{Text: manager.NextPageButton, CallbackData: nextCb})
+	}
+
+	return row, nil
+}
+
 func (h *Handler) confirmKeyboard(confirmStep manager.Step) (bot.ReplyMarkup, error) {
 	confirmCb, err := manager.CreateCallBack(manager.DebtHandler, confirmStep, "")
 	if err != nil {
@@ -238,8 +381,9 @@ func (h *Handler) redirectKeyboard(backH manager.TypeHandler, backS manager.Step
 }
 
 func truncate(s string, n int) string {
-	if len(s) <= n {
+	r := []rune(s)
+	if len(r) <= n {
 		return s
 	}
-	return s[:n-3] + "..."
+	return string(r[:n-3]) + "..."
 }
@@ -0,0 +1,83 @@
+package debt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/model"
+)
+
+const tableNameWidth = 16
+
+// debtTable — renders debts as a monospace table, ready to be sent with bot.ParseModeHTML,
+// rows are numbered starting from offset+1
+func debtTable(debts []*model.Debt, offset int) string {
+	rows := make([][]string, 0, len(debts)+1)
+	rows = append(rows, []string{
+		manager.ListTableNumHeader,
+		manager.ListTableNameHeader,
+		manager.ListTableAmountHeader,
+		manager.ListTableDateHeader,
+	})
+
+	for i, d := range debts {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", offset+i+1),
+			truncate(strings.ToUpper(d.Description), tableNameWidth),
+			manager.FormatMoney(d.Amount),
+			shortStatus(d),
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+
+			// amounts are right aligned, everything else is left aligned
+			if i == 2 {
+				sb.WriteString(padLeft(cell, widths[i]))
+				continue
+			}
+
+			sb.WriteString(padRight(cell, widths[i]))
+		}
+		sb.WriteString("\n")
+	}
+
+	return "<pre>" + bot.EscapeHTML(strings.TrimRight(sb.String(), "\n")) + "</pre>\n\n"
+}
+
+func shortStatus(debt *model.Debt) string {
+	if debt.ReturnDate == nil {
+		return manager.ListTableNoDate
+	}
+
+	days := int(time.Until(*debt.ReturnDate).Hours() / 24)
+	if days < 0 {
+		return fmt.Sprintf(manager.ListTableExpiredFormat, -days)
+	}
+
+	return fmt.Sprintf(manager.ListTableDaysFormat, days)
+}
+
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
+
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0)) + s
+}
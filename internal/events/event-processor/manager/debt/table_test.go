@@ -0,0 +1,74 @@
+package debt
+
+import (
+	"html"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/model"
+)
+
+func TestDebtTable(t *testing.T) {
+	debts := []*model.Debt{
+		{Description: "бур", Amount: 5},
+		{Description: "<Giga> & drill", Amount: 1500000},
+		{Description: "спиральный бур ядра вселенной", Amount: 1000},
+	}
+
+	got := debtTable(debts, 10)
+
+	if !strings.HasPrefix(got, "<pre>") || !strings.HasSuffix(got, "</pre>\n\n") {
+		t.Fatalf("table is not wrapped into <pre>: %q", got)
+	}
+	if strings.Contains(got, "<Giga>") {
+		t.Fatalf("description is not escaped: %q", got)
+	}
+
+	body := html.UnescapeString(strings.TrimSuffix(strings.TrimPrefix(got, "<pre>"), "</pre>\n\n"))
+	lines := strings.Split(body, "\n")
+
+	want := []struct {
+		num, name, amount string
+	}{
+		{"#", manager.ListTableNameHeader, manager.ListTableAmountHeader},
+		{"11", "БУР", "5"},
+		{"12", "<GIGA> & DRILL", "1.500.000"},
+		{"13", "СПИРАЛЬНЫЙ БУ...", "1.000"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), body)
+	}
+
+	// columns start and end at the same rune in every line, whatever the bytes per rune
+	nameAt := runeIndex(lines[0], manager.ListTableNameHeader)
+	amountEnd := runeIndex(lines[0], manager.ListTableAmountHeader) + utf8.RuneCountInString(manager.ListTableAmountHeader)
+
+	for i, w := range want {
+		line := lines[i]
+
+		if !strings.HasPrefix(line, w.num) {
+			t.Errorf("line %q does not start with %q", line, w.num)
+		}
+		if at := runeIndex(line, w.name); at != nameAt {
+			t.Errorf("name %q starts at rune %d of %q, want %d", w.name, at, line, nameAt)
+		}
+		if end := runeIndex(line, w.amount) + utf8.RuneCountInString(w.amount); end != amountEnd {
+			t.Errorf("amount %q ends at rune %d of %q, want %d", w.amount, end, line, amountEnd)
+		}
+		if n, first := utf8.RuneCountInString(line), utf8.RuneCountInString(lines[0]); n != first {
+			t.Errorf("line %q is %d runes wide, want %d", line, n, first)
+		}
+	}
+}
+
+// runeIndex — position of sub in s in runes, -1 when s does not contain it
+func runeIndex(s, sub string) int {
+	i := strings.Index(s, sub)
+	if i < 0 {
+		return -1
+	}
+
+	return utf8.RuneCountInString(s[:i])
+}
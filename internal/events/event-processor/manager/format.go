@@ -0,0 +1,18 @@
+package manager
+
+import (
+	"fmt"
+)
+
+// FormatMoney — amount with dots between thousands: 1000000 -> 1.000.000
+func FormatMoney(amount int64) string {
+	str := fmt.Sprintf("%d", amount)
+	var res []byte
+	for i, c := range str {
+		if i > 0 && (len(str)-i)%3 == 0 {
+			res = append(res, '.')
+		}
+		res = append(res, byte(c))
+	}
+	return string(res)
+}
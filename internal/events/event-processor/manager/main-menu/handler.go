@@ -92,6 +92,12 @@ func (h *Handler) mainKeyboard() (bot.ReplyMarkup, error) {
 		return bot.ReplyMarkup{}, err
 	}
 
+	profile, err := manager.CreateCallBack(manager.ProfileHandler, manager.StepStart, "")
+	if err != nil {
+		h.logger.Errorf("failed to create calldack, err:%v", err)
+		return bot.ReplyMarkup{}, err
+	}
+
 	ignore, err := manager.CreateCallBack(manager.IgnoreHandler, manager.StepIgnore, "")
 	if err != nil {
 		h.logger.Errorf("failed to create calldack, err:%v", err)
@@ -103,5 +109,6 @@ func (h *Handler) mainKeyboard() (bot.ReplyMarkup, error) {
 		{{Text: manager.RecipeModuleButton, CallbackData: ignore}},
 		{{Text: manager.GymModuleButton, CallbackData: ignore}},
 		{{Text: manager.TasksModuleButton, CallbackData: ignore}},
+		{{Text: manager.ProfileModuleButton, CallbackData: profile}},
 	}), nil
 }
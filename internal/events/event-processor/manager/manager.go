@@ -3,11 +3,16 @@ package manager
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
+	"time"
 
 	"drillCore/internal/bot"
 	"drillCore/internal/events"
+	"drillCore/internal/metrics"
 	"drillCore/internal/session"
+	"drillCore/internal/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -27,20 +32,40 @@ type Manager struct {
 	sesMng   SessionManager
 	logger   *zap.SugaredLogger
 	handlers map[TypeHandler]*Handler
+
+	commandKB  bot.ReplyMarkup
+	mainMenuKB bot.ReplyMarkup
 }
 
-func New(tg *bot.Client, sm SessionManager, logger *zap.SugaredLogger, handlers ...Handler) *Manager {
+// New — manager dispatching to the handlers, each handler is wrapped by the middlewares,
+// the first middleware is the outermost
+func New(tg *bot.Client, sm SessionManager, logger *zap.SugaredLogger, middlewares []Middleware, handlers ...Handler) *Manager {
 	p := &Manager{
 		tg:       tg,
 		logger:   logger,
 		sesMng:   sm,
-		handlers: registeredHandlers(handlers...),
+		handlers: registeredHandlers(middlewares, handlers...),
+
+		commandKB: CommandKeyboard(),
+	}
+
+	mainMenu, err := CreateCallBack(MainMenuHandler, StepStart, "")
+	if err != nil {
+		logger.Fatal(err)
 	}
 
+	p.mainMenuKB = bot.NewInlineKeyboard([][]bot.InlineKeyboardButton{
+		{{Text: MainMenuButtonGeneral, CallbackData: mainMenu}},
+	})
+
 	return p
 }
 
-func (m *Manager) HandleEvent(ctx context.Context, e *events.Event) error {
+func (m *Manager) HandleEvent(ctx context.Context, e *events.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "Manager.HandleEvent")
+	defer func() { tracing.End(span, err) }()
+	defer m.recoverPanic(ctx, e, &err)
+
 	m.logger.Debugf("handle event: %+v", e)
 
 	switch e.Type {
@@ -95,14 +120,14 @@ func (m *Manager) routeCallBack(ctx context.Context, e *events.Event) error {
 
 	m.logger.Debugf("found call back handler for user %d, h:%+v", e.Meta.ChatID, h)
 
-	return h.Handle(ctx, e)
+	return m.handle(ctx, h, cb.Step, e)
 }
 
 func (m *Manager) routeUserInput(ctx context.Context, e *events.Event) error {
 	m.logger.Debugf("route user input: %v", e.Text)
 
-	if _, isCmd := ParseCommand(e.Text); isCmd {
-		h, ok := m.handler(CMDHandler)
+	if cmd, isCmd := ParseCommand(e.Text); isCmd {
+		h, ok := m.handler(CommandHandler(cmd))
 		if !ok {
 			m.logger.Errorf(
 				"failed to find command handler, for user %d",
@@ -119,7 +144,7 @@ func (m *Manager) routeUserInput(ctx context.Context, e *events.Event) error {
 			)
 		}
 
-		return h.Handle(ctx, e)
+		return m.handle(ctx, h, StepStart, e)
 	}
 
 	ses, exists := m.sesMng.Get(ctx, e.Meta.UserID)
@@ -129,10 +154,11 @@ func (m *Manager) routeUserInput(ctx context.Context, e *events.Event) error {
 			e.Meta.UserID,
 		)
 
-		return m.tg.SendMessage(
+		return m.tg.SendMessageWithKeyboard(
 			ctx,
 			e.Meta.ChatID,
 			ButtonOnlyMode,
+			m.commandKB,
 		)
 	}
 
@@ -153,14 +179,66 @@ func (m *Manager) routeUserInput(ctx context.Context, e *events.Event) error {
 		)
 	}
 
+	return m.handle(ctx, h, state.Step, e)
+}
+
+// recoverPanic — error boundary of the handlers: the panic is logged with its stack, the user's
+// session is dropped as it may be half-written, and the user gets back to the main menu.
+// err becomes events.ErrPanic, so the event goes to the dead letters without retries.
+func (m *Manager) recoverPanic(ctx context.Context, e *events.Event, err *error) {
+	p := recover()
+	if p == nil {
+		return
+	}
+
+	metrics.HandlerPanics.Inc()
+	*err = fmt.Errorf("%w: %v", events.ErrPanic, p)
+
+	if e == nil || e.Meta == nil {
+		m.logger.Errorw("handler panicked", "panic", p, "stack", string(debug.Stack()))
+
+		return
+	}
+
+	m.logger.Errorw("handler panicked",
+		"panic", p,
+		"user_id", e.Meta.UserID,
+		"chat_id", e.Meta.ChatID,
+		"update_id", e.Meta.UpdateID,
+		"text", e.Text,
+		"trace_id", tracing.TraceID(ctx),
+		"stack", string(debug.Stack()),
+	)
+
+	if delErr := m.sesMng.Delete(ctx, e.Meta.UserID); delErr != nil {
+		m.logger.Errorf("failed to drop session of user %d after panic: %v", e.Meta.UserID, delErr)
+	}
+
+	if sendErr := m.tg.SendMessageWithKeyboard(ctx, e.Meta.ChatID, MsgSomethingBroke, m.mainMenuKB); sendErr != nil {
+		m.logger.Errorf("failed to send panic notice to user %d: %v", e.Meta.UserID, sendErr)
+	}
+}
+
+// handle — runs the handler and records its latency, commands are recorded as StepStart.
+// The span is ended and the latency recorded even when the handler panics.
+func (m *Manager) handle(ctx context.Context, h Handler, step Step, e *events.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "handler."+h.Type().String(), attribute.String("handler.step", step.String()))
+
+	start := time.Now()
+	defer func() {
+		metrics.HandlerDuration.WithLabelValues(h.Type().String(), step.String()).Observe(metrics.Since(start))
+		tracing.End(span, err)
+	}()
+
 	return h.Handle(ctx, e)
 }
 
-func registeredHandlers(handlers ...Handler) map[TypeHandler]*Handler {
+func registeredHandlers(middlewares []Middleware, handlers ...Handler) map[TypeHandler]*Handler {
 	m := make(map[TypeHandler]*Handler, len(handlers))
 
 	for _, h := range handlers {
-		m[h.Type()] = &h
+		wrapped := chain(h, middlewares...)
+		m[h.Type()] = &wrapped
 	}
 
 	return m
@@ -24,7 +24,8 @@ const (
 	MsgCMDHelp = SpiralDelimiter +
 		"🌀 SPIRAL COMMAND TRANSMISSION RECEIVED\n\n" +
 		"📡 /help — DISPLAY COMBAT MANUAL\n" +
-		"🌀 /start — INITIATE SPIRAL CORE\n\n" +
+		"🌀 /start — INITIATE SPIRAL CORE\n" +
+		"👤 /profile — PILOT PROFILE\n\n" +
 		"💥 ACTIVE DRILL HUBS:\n" +
 		"  " + DebtModuleButton + "\n\n" +
 		SpiralDelimiter +
@@ -118,6 +119,16 @@ const (
 
 	MainMenuButtonGeneral = "🌀 DEPLOY COMMAND CENTER 🌀"
 
+	CmdStartDescription   = "🌀 Initiate Spiral Core"
+	CmdHelpDescription    = "📡 Display combat manual"
+	CmdDebtDescription    = "🌀 Debt Drill Hub manual"
+	CmdRecipeDescription  = "🍲 Kitchen Drill Hub (in development)"
+	CmdGymDescription     = "🏋️ Gym Drill Hub (in development)"
+	CmdTaskDescription    = "📅 Task Drill Hub (in development)"
+	CmdProfileDescription = "👤 Pilot profile"
+
+	CommandKeyboardPlaceholder = "🌀 PRESS THE DRILL BUTTONS"
+
 	InvalidCommand = SpiralDelimiter +
 		"🚨 COMMAND REJECTED BY SPIRAL CORE! 💢\n\n" +
 		"💥 SPIRAL CORE ONLY RESPONDS TO PROPER ORDERS!\n" +
@@ -135,10 +146,11 @@ const (
 		"DEPLOY TARGET HUB:\n" +
 		SpiralDelimiter
 
-	DebtModuleButton   = "🌀 DEBT DRILL HUB 🌀"
-	RecipeModuleButton = "💢 KITCHEN DRILL HUB 💢"
-	GymModuleButton    = "💢 GYM DRILL HUB 💢"
-	TasksModuleButton  = "💢 TASK DRILL HUB 💢"
+	DebtModuleButton    = "🌀 DEBT DRILL HUB 🌀"
+	RecipeModuleButton  = "💢 KITCHEN DRILL HUB 💢"
+	GymModuleButton     = "💢 GYM DRILL HUB 💢"
+	TasksModuleButton   = "💢 TASK DRILL HUB 💢"
+	ProfileModuleButton = "👤 PILOT PROFILE 👤"
 )
 
 // GENERAL
@@ -181,6 +193,20 @@ const (
 		"🌀 RETURNING TO SAFE MODE...\n" +
 		SpiralDelimiter
 
+	MsgSomethingBroke = SpiralDelimiter +
+		"🚨 DRILL CORE MELTDOWN!\n\n" +
+		"💥 SOMETHING BROKE INSIDE THE SPIRAL\n" +
+		"⚠️ THE ENGINEERS ARE ALREADY ON IT\n\n" +
+		"🌀 RETURNING TO THE MAIN MENU...\n" +
+		SpiralDelimiter
+
+	MsgThrottled = SpiralDelimiter +
+		"🚨 DRILL OVERHEAT!\n\n" +
+		"💥 TOO MANY COMMANDS IN TOO LITTLE TIME\n" +
+		"⚠️ THE CORE IGNORES YOU WHILE IT COOLS DOWN\n\n" +
+		"🌀 CATCH YOUR BREATH AND TRY AGAIN IN A MINUTE...\n" +
+		SpiralDelimiter
+
 	SessionLost = SpiralDelimiter +
 		"🚨 SPIRAL CONNECTION LOST!\n\n" +
 		"💥 YOUR DRILL SESSION VANISHED INTO THE VOID\n" +
@@ -224,6 +250,7 @@ const (
 	PayDebtButton    = "💥 BALANCE PROTOCOL"
 	DeleteDebtButton = "💀 ANNIHILATE PROTOCOL"
 	ListDebtButton   = "📜 REVIEW CONTRACT LOG"
+	UndoButton       = "↩️ UNDO DRILL"
 
 	EditDescButton    = "🌀 RE-SET CONTRACT NAME"
 	EditAmountButton  = "💥 RE-SET SPIRAL POWER"
@@ -232,6 +259,27 @@ const (
 
 	RedirectDebtButton = "🌀↵ LOCK DRILLING TARGET"
 
+	DescriptionPlaceholder = "CONTRACT NAME"
+	AmountPlaceholder      = "SPIRAL POWER ₽"
+	SearchPlaceholder      = "PART OF CONTRACT NAME"
+
+	PrevPageButton   = "◀ PREV"
+	NextPageButton   = "NEXT ▶"
+	PageButtonFormat = "%d/%d"
+	SearchButton     = "🔍 SCAN CONTRACTS"
+
+	ActiveOptionFormat = "✓ %s"
+
+	SortByDueDateButton = "⏳ D-DAY"
+	SortByAmountButton  = "💥 POWER"
+	SortByCreatedButton = "🆕 NEWEST"
+	SortByNameButton    = "🔤 NAME"
+
+	FilterAllButton          = "🌀 ALL"
+	FilterOverdueButton      = "☠️ OVERDUE"
+	FilterDueThisMonthButton = "📅 MONTH"
+	FilterNoDateButton       = "🌌 NO DATE"
+
 	MsgDebtMenu = SpiralDelimiter +
 		"🌀 SPIRAL DEBT MODULE — ONLINE\n\n" +
 		"💥 YOUR DEBTS ARE NOT LIMITS —\n" +
@@ -240,9 +288,13 @@ const (
 		"🌀 AWAITING DRILL ORDERS!\n" +
 		SpiralDelimiter
 
-	ListDebtFormat = "%s %s\n\t" +
-		"💥 SPIRAL POWER: %s₽\n\t" +
-		"%s\n\n"
+	ListTableNumHeader     = "#"
+	ListTableNameHeader    = "CONTRACT"
+	ListTableAmountHeader  = "POWER ₽"
+	ListTableDateHeader    = "D-DAY"
+	ListTableDaysFormat    = "%dD"
+	ListTableExpiredFormat = "-%dD !!"
+	ListTableNoDate        = "∞"
 
 	ListTotalAmountFormat       = "💥 TOTAL SPIRAL POWER REQUIRED: %s₽\n\n"
 	ListReturnDateFormat        = "⏳ D-DAY: %d DAYS REMAINING"
@@ -261,12 +313,24 @@ const (
 	MsgEditStart = "🌀 INITIATE SPIRAL RECALIBRATE PROTOCOL...\n\n" +
 		"💥 SELECT SPIRAL CONTRACT EDIT DRILLING:"
 
-	MsgAddAmount = "🌀 CONTRACT NAME LOCKED: %s\n\n" +
+	MsgAddAmount = "🌀 CONTRACT NAME LOCKED: <b>%s</b>\n\n" +
 		"🌀 INITIATE QUANTUM DRILLING!\n\n" +
 		"💥 INPUT SPIRAL POWER:"
 
 	MsgAddDate = "🌀 SPIRAL POWER LOCKED: %s₽\n\n"
 
+	MsgSelectPage = "🌀 SPIRAL CONTRACT MATRIX\n\n" +
+		"💥 SELECT SPIRAL CONTRACT:"
+
+	MsgPageFormat        = "\n\n📜 PAGE %d/%d — %d CONTRACTS"
+	MsgSearchQueryFormat = "\n🔍 SCAN FILTER: <b>%s</b>"
+
+	MsgEnterSearch = "🔍 INITIATE CONTRACT SCAN!\n\n" +
+		"💥 INPUT PART OF CONTRACT NAME:"
+
+	MsgSearchEmpty = "🔍 SCAN COMPLETE — NO CONTRACT MATCHES: <b>%s</b>\n\n" +
+		"🌀 RE-SCAN OR ABORT DRILLING"
+
 	MsgEditMenu = "🌀 RECALIBRATE PROTOCOL READY!\n\n" +
 		"CHOOSE COMPONENT TO DEEP-DRILLING:"
 
@@ -279,7 +343,7 @@ const (
 	MsgEnterDescription = "🌀 INITIATE CORE DRILLING!\n\n" +
 		"💥 INPUT NEW CONTRACT NAME:"
 	MsgEditDescription = "🌀 CORE DRILLING SUCCESS!\n\n" +
-		"💥 CONTRACT RECALIBRATED TO: %s\n\n" +
+		"💥 CONTRACT RECALIBRATED TO: <b>%s</b>\n\n" +
 		"🌀 RETURNING TO RECALIBRATE DRILL SEQUENCE..."
 
 	MsgEnterDate = "🌀 INITIATE TEMPORAL DRILLING!\n\n" +
@@ -289,47 +353,47 @@ const (
 		"🌀 RETURNING TO RECALIBRATE DRILL SEQUENCE..."
 
 	MsgSavedDebt = "🌀 SPIRAL CONTRACT DEPLOYED!\n\n" +
-		"🌀 CONTRACT: %s\n" +
+		"🌀 CONTRACT: <b>%s</b>\n" +
 		"💥 SPIRAL POWER: %s₽\n" +
 		"⏳ D-DAY: %s\n\n" +
 		"🌀 THIS CONTRACT IS NOW PART OF THE DRILL LOG\n" +
 		"🌀 RETURNING TO COMMAND SEQUENCE..."
 
 	MsgDebtSelected = "🌀 SPIRAL CONTRACT LOCKED!\n\n" +
-		"🌀 CONTRACT: %s\n" +
+		"🌀 CONTRACT: <b>%s</b>\n" +
 		"💥 SPIRAL POWER: %s₽\n\n" +
 		"%s\n\n" +
 		"🌀 TARGET LOCKED — NEXT PROTOCOL PENDING\n"
 
 	MsgConfirmDeleteWarning = "☠️ ANNIHILATE DRILL SEQUENCE INITIATED!\n\n" +
 		"💀 THIS WILL ERASE THE SPIRAL CONTRACT FROM EXISTENCE\n\n" +
-		"🚨 WARNING: THIS ACTION CANNOT BE UNDONE\n" +
+		"🚨 WARNING: UNDO IS ONLY POSSIBLE RIGHT AFTER THE STRIKE\n" +
 		"⚡ THE DRILL WILL PIERCE THROUGH SPACE-TIME\n\n" +
 		"🌀 COMMIT TOTAL ANNIHILATION?"
 
 	MsgDeleteDebt = "💀 SPIRAL CONTRACT ERASED!\n\n" +
-		"🌀 CONTRACT: %s\n" +
+		"🌀 CONTRACT: <b>%s</b>\n" +
 		"💥 SPIRAL POWER: %s₽\n\n" +
 		"🌀 THE CONTRACT HAS BEEN DRILLED OUT OF REALITY\n" +
 		"🌀 RETURNING TO COMMAND SEQUENCE..."
 
 	MsgPayConfirm = "🌀 BALANCE PROTOCOL FINAL LOCK!\n\n" +
-		"🌀 CONTRACT: %s\n\n" +
+		"🌀 CONTRACT: <b>%s</b>\n\n" +
 		"💥 SPIRAL POWER: %s₽\n" +
 		"🌀 PAYMENT ENERGY: %s₽\n" +
 		"💥 RESIDUAL POWER: %s₽\n\n" +
 		"🌀 COMPLETE?"
 	MsgPayToDelete = "💥 SPIRAL CONTRACT ANNIHILATED!\n\n" +
-		"🌀 CONTRACT:\"%s\" ERASED FROM EXISTENCE\n\n" +
+		"🌀 CONTRACT: <b>%s</b> ERASED FROM EXISTENCE\n\n" +
 		"🌀 RETURNING TO COMMAND SEQUENCE..."
 	MsgPayToUpdate = "🌀 IF THE DEBT IS THIS BIG…\n" +
 		"THEN OUR DRILL MUST BE EVEN BIGGER!\n\n" +
-		"🌀 CONTRACT: %s\n" +
+		"🌀 CONTRACT: <b>%s</b>\n" +
 		"💥 RESIDUAL SPIRAL POWER: %s₽\n\n" +
 		"🌀 RETURNING TO COMMAND SEQUENCE..."
 
 	MsgFinishEdit = "🌀 RECALIBRATE PROTOCOL COMPLETE!\n\n" +
-		"💥 SPIRAL CONTRACT: %s\n" +
+		"💥 SPIRAL CONTRACT: <b>%s</b>\n" +
 		"🌀 SPIRAL POWER: %s₽\n\n" +
 		"%s\n\n" +
 		"🌀 RETURNING TO COMMAND SEQUENCE..."
@@ -367,6 +431,30 @@ const (
 		"🌀 RE-ENTER VALID SPIRAL POWER:\n" +
 		SpiralDelimiter
 
+	MsgInvalidSearchEmpty = SpiralDelimiter +
+		"🚨 SCAN REJECTED: FILTER IS EMPTY!\n\n" +
+		"🌀 RE-ENTER PART OF CONTRACT NAME:\n" +
+		SpiralDelimiter
+
+	MsgInvalidSearchLength = SpiralDelimiter +
+		"🚨 SCAN REJECTED: FILTER EXCEEDS LIMIT!\n\n" +
+		"💥 CURRENT: %d/100 CHARACTERS\n\n" +
+		"🌀 RE-ENTER PART OF CONTRACT NAME:\n" +
+		SpiralDelimiter
+
+	ListPageFormat = "📜 PAGE %d/%d — %d CONTRACTS\n\n"
+
+	MsgListFilterEmpty = SpiralDelimiter +
+		"🔍 NO SPIRAL CONTRACTS MATCH THE ACTIVE FILTER!\n\n" +
+		"🌀 SWITCH THE FILTER TO SCAN AGAIN\n" +
+		SpiralDelimiter
+
+	MsgFailedToSaveListSettings = SpiralDelimiter +
+		"🚨 FAILED TO LOCK CONTRACT LOG VIEW!\n\n" +
+		"💥 DRILLING FAILURE!\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
 	MsgDateNotSet = SpiralDelimiter +
 		"🚨 TEMPORAL COORDINATES LOST!\n\n" +
 		"💥 DRILL CANNOT PIERCE THE VOID OF TIME\n\n" +
@@ -387,13 +475,6 @@ const (
 		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
 		SpiralDelimiter
 
-	MsgUserIdNotEqualDebtId = SpiralDelimiter +
-		"🚨 DRILL COLLISION DETECTED!\n\n" +
-		"💥 THIS SPIRAL CONTRACT BELONGS TO ANOTHER PILOT\n\n" +
-		"⚠️ YOU CANNOT PIERCE ANOTHER MAN'S SOUL ⚔️\n\n" +
-		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
-		SpiralDelimiter
-
 	MsgFailedToSaveDebt = SpiralDelimiter +
 		"🚨 SPIRAL CONTRACT REGISTRY REJECTED!\n\n" +
 		"💥 DRILLING FAILURE!\n\n" +
@@ -415,6 +496,38 @@ const (
 		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
 		SpiralDelimiter
 
+	MsgDebtChangedMeanwhile = "🚨 SPIRAL CONTRACT CHANGED MEANWHILE!\n\n" +
+		"⚠️ ANOTHER DRILL HIT THIS CONTRACT FIRST\n\n" +
+		"🌀 CONTRACT: <b>%s</b>\n" +
+		"💥 SPIRAL POWER: %s₽\n\n" +
+		"%s\n\n" +
+		"🌀 ACTUAL STATE RELOADED — REPEAT THE DRILL\n"
+
+	MsgUndoDone = "↩️ SPIRAL TIME REWOUND!\n\n" +
+		"🌀 CONTRACT: <b>%s</b>\n" +
+		"💥 SPIRAL POWER: %s₽\n\n" +
+		"%s\n\n" +
+		"🌀 THE CONTRACT IS BACK IN THE DRILL LOG\n"
+
+	MsgNothingToUndo = SpiralDelimiter +
+		"🚨 NOTHING TO REWIND!\n\n" +
+		"⏳ THE UNDO WINDOW IS CLOSED OR THE CONTRACT CHANGED SINCE\n\n" +
+		"🌀 RETURNING TO COMMAND SEQUENCE...\n" +
+		SpiralDelimiter
+
+	MsgFailedToUndo = SpiralDelimiter +
+		"🚨 SPIRAL TIME REWIND REJECTED!\n\n" +
+		"💥 DRILLING FAILURE!\n\n" +
+		"⚠️ SPIRAL COLLAPSE DETECTED — UNIVERSE RESISTS OUR DRILL\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
+	MsgDebtDeletedMeanwhile = SpiralDelimiter +
+		"🚨 SPIRAL CONTRACT VANISHED MEANWHILE!\n\n" +
+		"💀 IT WAS ANNIHILATED BY ANOTHER DRILL\n\n" +
+		"🌀 RETURNING TO COMMAND SEQUENCE...\n" +
+		SpiralDelimiter
+
 	FailedToGetDebts = SpiralDelimiter +
 		"🚨 SPIRAL CORE CORRUPTED!\n\n" +
 		"💥 SPIRAL MATRIX OFFLINE!\n" +
@@ -535,3 +648,174 @@ const (
 
 	RedirectDateButton = "🌀↵ LOCK TEMPORAL DRILL" // REDIRECT TO PARENT HANDLER
 )
+
+// PROFILE HANDLER
+const (
+	MsgProfileFormat = SpiralDelimiter +
+		"👤 PILOT PROFILE\n\n" +
+		"🆔 ID: %d\n" +
+		"🏷 NAME: %s\n\n" +
+		"🗣 LANGUAGE: %s\n" +
+		"🌐 LOCALE: %s\n" +
+		"🕰 TIME ZONE: %s\n\n" +
+		"🚀 JOINED THE SPIRAL: %s (%d DAYS AGO)\n" +
+		"📡 LAST SIGNAL: %s\n" +
+		SpiralDelimiter +
+		"💥 ACTIVE CONTRACTS: %d\n" +
+		"💰 TOTAL AMOUNT: %s\n" +
+		"☠️ OVERDUE: %d\n" +
+		SpiralDelimiter
+
+	ProfileUnknownName     = "NAMELESS PILOT"
+	ProfileUnknownLanguage = "UNKNOWN"
+
+	MsgFailedToGetProfile = SpiralDelimiter +
+		"🚨 PILOT RECORDS OFFLINE!\n\n" +
+		"⚠️ FAILED TO READ YOUR PROFILE\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+)
+
+// ADMIN HANDLER
+const (
+	MsgAuditUsage = SpiralDelimiter +
+		"🛠 AUDIT DRILL USAGE:\n\n" +
+		"/audit USER_ID — LAST SPIRAL MUTATIONS OF THE PILOT\n" +
+		SpiralDelimiter
+
+	MsgAuditTrailFormat = "🛠 AUDIT TRAIL OF PILOT %d (LAST %d)\n\n%s"
+
+	MsgAuditEmpty = SpiralDelimiter +
+		"🛠 AUDIT TRAIL IS EMPTY\n\n" +
+		"🌀 THIS PILOT NEVER TOUCHED A SPIRAL CONTRACT\n" +
+		SpiralDelimiter
+
+	MsgFailedToGetAudit = SpiralDelimiter +
+		"🚨 AUDIT MATRIX OFFLINE!\n\n" +
+		"⚠️ FAILED TO SCAN AUDIT TRAIL\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
+	AuditNoDate = "—"
+
+	MsgDeadLettersFormat = "🛠 DEAD LETTERS (LAST %d)\n\n%s\n\n" +
+		"/replay ID — RETRY THE UPDATE\n" +
+		"/discard ID — DROP IT FOR GOOD"
+
+	MsgDeadLettersEmpty = SpiralDelimiter +
+		"🛠 NO DEAD LETTERS\n\n" +
+		"🌀 EVERY UPDATE PIERCED THROUGH\n" +
+		SpiralDelimiter
+
+	MsgDeadLetterUsage = SpiralDelimiter +
+		"🛠 DEAD LETTER DRILL USAGE:\n\n" +
+		"/deadletters — FAILED UPDATES\n" +
+		"/replay ID — RETRY THE UPDATE\n" +
+		"/discard ID — DROP IT FOR GOOD\n" +
+		SpiralDelimiter
+
+	MsgDeadLetterReplayQueued = "🔁 DEAD LETTER #%d QUEUED FOR REPLAY"
+
+	MsgDeadLetterDiscarded = "🗑 DEAD LETTER #%d DISCARDED"
+
+	MsgDeadLetterNotFound = "⚠️ DEAD LETTER #%d NOT FOUND"
+
+	MsgAccessUsage = SpiralDelimiter +
+		"🛠 ACCESS DRILL USAGE:\n\n" +
+		"/ban USER_ID — CUT THE PILOT OFF THE SPIRAL\n" +
+		"/unban USER_ID — LET THE PILOT BACK\n" +
+		"/allow USER_ID — ADD THE PILOT TO THE ALLOWLIST\n" +
+		"/revoke USER_ID — REMOVE THE PILOT FROM THE ALLOWLIST\n" +
+		SpiralDelimiter
+
+	MsgAccessChanged = "🛡 %s DONE FOR PILOT %d"
+
+	MsgAccessAllowlistOff = "\n⚠️ ALLOWLIST MODE IS OFF, EVERY PILOT PASSES ANYWAY"
+
+	MsgAccessAdminImmune = "⚠️ PILOT %d IS AN ADMIN, ACCESS OF ADMINS IS SET IN CONFIG"
+
+	MsgFailedToChangeAccess = SpiralDelimiter +
+		"🚨 ACCESS MATRIX OFFLINE!\n\n" +
+		"⚠️ FAILED TO CHANGE ACCESS OF THE PILOT\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
+	MsgStatsFormat = "🛠 SPIRAL STATS\n\n%s"
+
+	MsgFailedToGetStats = SpiralDelimiter +
+		"🚨 STATS MATRIX OFFLINE!\n\n" +
+		"⚠️ FAILED TO COUNT THE SPIRAL\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
+	MsgUserUsage = SpiralDelimiter +
+		"🛠 PILOT DRILL USAGE:\n\n" +
+		"/user USER_ID — CONTRACTS AND SESSION OF THE PILOT\n" +
+		SpiralDelimiter
+
+	MsgUserFormat = "🛠 PILOT %d\n\n%s"
+
+	MsgFailedToGetUser = SpiralDelimiter +
+		"🚨 PILOT MATRIX OFFLINE!\n\n" +
+		"⚠️ FAILED TO SCAN THE PILOT\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
+	MsgSessionsFormat = "🛠 ACTIVE SESSIONS (%d)\n\n%s\n\n" +
+		"/sessions USER_ID — CLEAR A STUCK SESSION"
+
+	MsgSessionsEmpty = SpiralDelimiter +
+		"🛠 NO ACTIVE SESSIONS\n\n" +
+		"🌀 EVERY PILOT IS RESTING\n" +
+		SpiralDelimiter
+
+	MsgSessionsUsage = SpiralDelimiter +
+		"🛠 SESSION DRILL USAGE:\n\n" +
+		"/sessions — ACTIVE SESSIONS\n" +
+		"/sessions USER_ID — CLEAR A STUCK SESSION\n" +
+		SpiralDelimiter
+
+	MsgSessionCleared = "🧹 SESSION OF PILOT %d CLEARED"
+
+	MsgSessionNotFound = "⚠️ PILOT %d HAS NO SESSION"
+
+	MsgBroadcastUsage = SpiralDelimiter +
+		"🛠 BROADCAST DRILL USAGE:\n\n" +
+		"/broadcast TEXT — SEND TEXT TO EVERY PILOT, A PREVIEW COMES FIRST\n" +
+		"/broadcasts — LATEST BROADCASTS AND THEIR DELIVERY\n" +
+		SpiralDelimiter
+
+	MsgBroadcastPreviewFormat = "📣 BROADCAST PREVIEW FOR %d PILOTS\n" +
+		SpiralDelimiter +
+		"%s\n" +
+		SpiralDelimiter
+
+	MsgBroadcastQueuedFormat = "📣 BROADCAST #%d QUEUED FOR %d PILOTS, THE REPORT COMES WHEN IT IS DELIVERED"
+
+	MsgBroadcastDoneFormat = "📣 BROADCAST #%d DONE: %d DELIVERED, %d FAILED, %d BLOCKED THE BOT, %d SKIPPED"
+
+	MsgBroadcastsFormat = "📣 BROADCASTS (LAST %d)\n\n%s"
+
+	MsgBroadcastsEmpty = SpiralDelimiter +
+		"📣 NO BROADCASTS YET\n\n" +
+		"🌀 THE SPIRAL IS QUIET\n" +
+		SpiralDelimiter
+
+	MsgBroadcastCanceled = "📣 BROADCAST DROPPED"
+
+	MsgBroadcastExpired = "⚠️ NO BROADCAST WAITS FOR CONFIRMATION, START AGAIN WITH /broadcast"
+
+	MsgFailedToBroadcast = SpiralDelimiter +
+		"🚨 BROADCAST MATRIX OFFLINE!\n\n" +
+		"⚠️ FAILED TO REACH THE PILOTS\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+
+	BroadcastSendButton = "📣 SEND TO EVERY PILOT"
+
+	MsgFailedToGetDeadLetters = SpiralDelimiter +
+		"🚨 DEAD LETTER MATRIX OFFLINE!\n\n" +
+		"⚠️ FAILED TO SCAN DEAD LETTERS\n\n" +
+		"🌀 REBOOTING DRILL PROTOCOLS...\n" +
+		SpiralDelimiter
+)
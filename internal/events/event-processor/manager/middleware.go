@@ -0,0 +1,102 @@
+package manager
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"drillCore/internal/events"
+	"drillCore/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// Middleware — wraps a handler with a cross-cutting concern, the handler type stays the same
+type Middleware func(next Handler) Handler
+
+type handlerFunc struct {
+	t  TypeHandler
+	fn func(ctx context.Context, e *events.Event) error
+}
+
+func (h handlerFunc) Type() TypeHandler {
+	return h.t
+}
+
+func (h handlerFunc) Handle(ctx context.Context, e *events.Event) error {
+	return h.fn(ctx, e)
+}
+
+// HandlerFunc — Handler of type t calling fn, middlewares build their wrappers with it
+func HandlerFunc(t TypeHandler, fn func(ctx context.Context, e *events.Event) error) Handler {
+	return handlerFunc{t: t, fn: fn}
+}
+
+// chain — h wrapped by the middlewares, the first one is the outermost
+func chain(h Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	return h
+}
+
+// Logging — one line per handled event with its latency and error
+func Logging(logger *zap.SugaredLogger) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(next.Type(), func(ctx context.Context, e *events.Event) error {
+			start := time.Now()
+			err := next.Handle(ctx, e)
+
+			fields := []any{
+				"handler", next.Type().String(),
+				"user_id", e.Meta.UserID,
+				"update_id", e.Meta.UpdateID,
+				"duration", time.Since(start),
+			}
+
+			if err != nil {
+				logger.Warnw("event handled with error", append(fields, "error", err)...)
+			} else {
+				logger.Infow("event handled", fields...)
+			}
+
+			return err
+		})
+	}
+}
+
+// SettingsLoader — storage of the user settings
+type SettingsLoader interface {
+	ListSettings(ctx context.Context, userID int64) (*model.ListSettings, error)
+}
+
+type listSettingsKey struct{}
+
+// Settings — loads the user's list settings into the context before the handlers of the given types,
+// of every type when none are given. Handlers read them with ListSettingsFromContext.
+func Settings(loader SettingsLoader, logger *zap.SugaredLogger, types ...TypeHandler) Middleware {
+	return func(next Handler) Handler {
+		if len(types) > 0 && !slices.Contains(types, next.Type()) {
+			return next
+		}
+
+		return HandlerFunc(next.Type(), func(ctx context.Context, e *events.Event) error {
+			settings, err := loader.ListSettings(ctx, int64(e.Meta.UserID))
+			if err != nil {
+				// not fatal, the handler loads them itself
+				logger.Warnw("failed to load user settings", "user_id", e.Meta.UserID, "error", err)
+
+				return next.Handle(ctx, e)
+			}
+
+			return next.Handle(context.WithValue(ctx, listSettingsKey{}, settings), e)
+		})
+	}
+}
+
+// ListSettingsFromContext — list settings stored by the Settings middleware
+func ListSettingsFromContext(ctx context.Context) (*model.ListSettings, bool) {
+	s, ok := ctx.Value(listSettingsKey{}).(*model.ListSettings)
+	return s, ok && s != nil
+}
@@ -0,0 +1,175 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"drillCore/internal/bot"
+	"drillCore/internal/events"
+	"drillCore/internal/events/event-processor/manager"
+	"drillCore/internal/model"
+
+	"go.uber.org/zap"
+)
+
+type Users interface {
+	User(ctx context.Context, userID int64) (*model.User, error)
+}
+
+type Storage interface {
+	DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error)
+}
+
+type Handler struct {
+	tg      *bot.Client
+	users   Users
+	storage Storage
+	logger  *zap.SugaredLogger
+
+	profileKB bot.ReplyMarkup
+}
+
+func New(tg *bot.Client, users Users, storage Storage, logger *zap.SugaredLogger) *Handler {
+	h := &Handler{
+		tg:      tg,
+		users:   users,
+		storage: storage,
+		logger:  logger,
+	}
+
+	kb, err := h.profileKeyboard()
+	if err != nil {
+		h.logger.Fatal(err)
+	}
+
+	h.profileKB = kb
+
+	return h
+}
+
+func (h *Handler) Type() manager.TypeHandler {
+	return manager.ProfileHandler
+}
+
+func (h *Handler) Handle(ctx context.Context, e *events.Event) error {
+	h.logger.Debugw("handling event in ", "handler", manager.ProfileHandler, "event", e)
+
+	switch e.Type {
+	case events.Message:
+		return h.sendProfile(ctx, e.Meta.ChatID, e.Meta.UserID)
+
+	case events.Callback:
+		cb, err := manager.ParseCallBack(e.Text)
+		if err != nil {
+			return h.tg.SendMessage(
+				ctx,
+				e.Meta.ChatID,
+				manager.FailedToGetCallBack,
+			)
+		}
+
+		if cb.Step != manager.StepStart {
+			return h.tg.SendMessage(
+				ctx,
+				e.Meta.ChatID,
+				fmt.Sprintf(
+					manager.InvalidStep,
+					cb.Step,
+				),
+			)
+		}
+
+		return h.sendProfile(ctx, e.Meta.ChatID, e.Meta.UserID)
+
+	default:
+		return h.tg.SendMessage(
+			ctx,
+			e.Meta.ChatID,
+			manager.InvalidEventType,
+		)
+	}
+}
+
+func (h *Handler) sendProfile(ctx context.Context, chatID, userID int) error {
+	u, err := h.users.User(ctx, int64(userID))
+	if err != nil {
+		h.logger.Errorf("failed to get user %d: %v", userID, err)
+
+		return h.tg.SendMessageWithKeyboard(ctx, chatID, manager.MsgFailedToGetProfile, h.profileKB)
+	}
+
+	all, err := h.storage.DebtsPage(ctx, int64(userID), 0, 1, nil)
+	if err != nil {
+		h.logger.Errorf("failed to get debts of user %d: %v", userID, err)
+
+		return h.tg.SendMessageWithKeyboard(ctx, chatID, manager.MsgFailedToGetProfile, h.profileKB)
+	}
+
+	overdue, err := h.storage.DebtsPage(ctx, int64(userID), 0, 1, &model.ListSettings{Filter: model.FilterOverdue})
+	if err != nil {
+		h.logger.Errorf("failed to get overdue debts of user %d: %v", userID, err)
+
+		return h.tg.SendMessageWithKeyboard(ctx, chatID, manager.MsgFailedToGetProfile, h.profileKB)
+	}
+
+	return h.tg.SendMessageWithKeyboard(ctx, chatID, profileText(u, all, overdue, time.Now()), h.profileKB)
+}
+
+// profileText — dates are shown in the time zone of the user, UTC when it is unknown
+func profileText(u *model.User, all, overdue *model.DebtPage, now time.Time) string {
+	loc, err := time.LoadLocation(u.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return fmt.Sprintf(
+		manager.MsgProfileFormat,
+		u.ID,
+		displayName(u),
+		orDefault(u.LanguageCode, manager.ProfileUnknownLanguage),
+		u.Locale,
+		loc.String(),
+		u.FirstSeen.In(loc).Format("02.01.2006"),
+		int(now.Sub(u.FirstSeen).Hours()/24),
+		u.LastSeen.In(loc).Format("02.01.2006 15:04"),
+		all.Total,
+		manager.FormatMoney(all.TotalAmount),
+		overdue.Total,
+	)
+}
+
+func displayName(u *model.User) string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+
+	switch {
+	case name != "" && u.Username != "":
+		return fmt.Sprintf("%s (@%s)", name, u.Username)
+	case name != "":
+		return name
+	case u.Username != "":
+		return "@" + u.Username
+	default:
+		return manager.ProfileUnknownName
+	}
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func (h *Handler) profileKeyboard() (bot.ReplyMarkup, error) {
+	mainMenu, err := manager.CreateCallBack(manager.MainMenuHandler, manager.StepStart, "")
+	if err != nil {
+		h.logger.Errorf("failed to create calldack, err:%v", err)
+		return bot.ReplyMarkup{}, err
+	}
+
+	return bot.NewInlineKeyboard([][]bot.InlineKeyboardButton{
+		{{Text: manager.MainMenuButton, CallbackData: mainMenu}},
+	}), nil
+}
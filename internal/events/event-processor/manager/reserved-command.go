@@ -1,5 +1,11 @@
 package manager
 
+import (
+	"strings"
+
+	"drillCore/internal/bot"
+)
+
 type ReservedCommand string
 
 const (
@@ -9,6 +15,22 @@ const (
 	Recipe ReservedCommand = "/recipe"
 	Gym    ReservedCommand = "/gym"
 	Task   ReservedCommand = "/task"
+
+	Profile ReservedCommand = "/profile"
+
+	Audit       ReservedCommand = "/audit"
+	DeadLetters ReservedCommand = "/deadletters"
+	Replay      ReservedCommand = "/replay"
+	Discard     ReservedCommand = "/discard"
+	Ban         ReservedCommand = "/ban"
+	Unban       ReservedCommand = "/unban"
+	Allow       ReservedCommand = "/allow"
+	Revoke      ReservedCommand = "/revoke"
+	Stats       ReservedCommand = "/stats"
+	User        ReservedCommand = "/user"
+	Broadcast   ReservedCommand = "/broadcast"
+	Broadcasts  ReservedCommand = "/broadcasts"
+	Sessions    ReservedCommand = "/sessions"
 )
 
 var reservedCommands = map[ReservedCommand]struct{}{
@@ -18,10 +40,96 @@ var reservedCommands = map[ReservedCommand]struct{}{
 	Recipe: {},
 	Gym:    {},
 	Task:   {},
+
+	Profile: {},
 }
 
+// adminCommands — handled by AdminHandler, never shown in the command menu
+var adminCommands = map[ReservedCommand]struct{}{
+	Audit:       {},
+	DeadLetters: {},
+	Replay:      {},
+	Discard:     {},
+	Ban:         {},
+	Unban:       {},
+	Allow:       {},
+	Revoke:      {},
+	Stats:       {},
+	User:        {},
+	Broadcast:   {},
+	Broadcasts:  {},
+	Sessions:    {},
+}
+
+// commandMenu — order and descriptions of the telegram "/" menu, must match the handlers of CommandHandler
+var commandMenu = []struct {
+	cmd         ReservedCommand
+	description string
+}{
+	{cmd: Start, description: CmdStartDescription},
+	{cmd: Help, description: CmdHelpDescription},
+	{cmd: Debt, description: CmdDebtDescription},
+	{cmd: Recipe, description: CmdRecipeDescription},
+	{cmd: Gym, description: CmdGymDescription},
+	{cmd: Task, description: CmdTaskDescription},
+	{cmd: Profile, description: CmdProfileDescription},
+}
+
+// ParseCommand — command in the first word of the text, the rest is returned by CommandArgs
 func ParseCommand(text string) (ReservedCommand, bool) {
-	cmd := ReservedCommand(text)
-	_, exists := reservedCommands[cmd]
-	return cmd, exists
+	name, _, _ := strings.Cut(strings.TrimSpace(text), " ")
+	cmd := ReservedCommand(name)
+
+	if _, exists := reservedCommands[cmd]; exists {
+		return cmd, true
+	}
+
+	return cmd, IsAdminCommand(cmd)
+}
+
+func IsAdminCommand(cmd ReservedCommand) bool {
+	_, exists := adminCommands[cmd]
+	return exists
+}
+
+// CommandHandler — handler of the command, commands with a screen of their own skip command.Handler
+func CommandHandler(cmd ReservedCommand) TypeHandler {
+	switch {
+	case IsAdminCommand(cmd):
+		return AdminHandler
+	case cmd == Profile:
+		return ProfileHandler
+	default:
+		return CMDHandler
+	}
+}
+
+// CommandArgs — words after the command
+func CommandArgs(text string) []string {
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return nil
+	}
+
+	return fields[1:]
+}
+
+// BotCommands — reserved commands in the setMyCommands format
+func BotCommands() []bot.BotCommand {
+	res := make([]bot.BotCommand, 0, len(commandMenu))
+	for _, c := range commandMenu {
+		res = append(res, bot.BotCommand{
+			Command:     strings.TrimPrefix(string(c.cmd), "/"),
+			Description: c.description,
+		})
+	}
+
+	return res
+}
+
+// CommandKeyboard — persistent reply keyboard with the main reserved commands
+func CommandKeyboard() bot.ReplyMarkup {
+	return bot.NewReplyKeyboard([][]bot.KeyboardButton{
+		{{Text: string(Start)}, {Text: string(Debt)}, {Text: string(Help)}},
+	}, CommandKeyboardPlaceholder)
 }
@@ -1,7 +1,6 @@
 package manager
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -17,6 +16,8 @@ const (
 	DateHandler
 	MainMenuHandler
 	DebtHandler
+	AdminHandler
+	ProfileHandler
 )
 
 type Step int
@@ -49,8 +50,80 @@ const (
 	StepDeleteConfirm
 	StepDeleteFinish
 	StepAddFinish
+	StepSelectPage
+	StepSearchStart
+	StepSearch
+	StepListSort
+	StepListFilter
+	StepUndo
+	StepBroadcastConfirm
+	StepBroadcastSend
+	StepBroadcastCancel
 )
 
+var handlerNames = [...]string{
+	IgnoreHandler:   "ignore",
+	CMDHandler:      "command",
+	DateHandler:     "date",
+	MainMenuHandler: "main_menu",
+	DebtHandler:     "debt",
+	AdminHandler:    "admin",
+	ProfileHandler:  "profile",
+}
+
+func (t TypeHandler) String() string {
+	if t >= 0 && int(t) < len(handlerNames) {
+		return handlerNames[t]
+	}
+	return fmt.Sprintf("handler(%d)", int(t))
+}
+
+var stepNames = [...]string{
+	StepIgnore:           "ignore",
+	StepStart:            "start",
+	StepList:             "list",
+	StepAddStart:         "add_start",
+	StepSelect:           "select",
+	StepEditStart:        "edit_start",
+	StepPayStart:         "pay_start",
+	StepEnterPayment:     "enter_payment",
+	StepPayAmount:        "pay_amount",
+	StepPayFinish:        "pay_finish",
+	StepYear:             "year",
+	StepMonth:            "month",
+	StepDay:              "day",
+	StepDeleteStart:      "delete_start",
+	StepAddAmount:        "add_amount",
+	StepAddDescription:   "add_description",
+	StepEditMenu:         "edit_menu",
+	StepEnterDate:        "enter_date",
+	StepEditDate:         "edit_date",
+	StepEnterAmount:      "enter_amount",
+	StepEditAmount:       "edit_amount",
+	StepEnterDescription: "enter_description",
+	StepEditDescription:  "edit_description",
+	StepEditFinish:       "edit_finish",
+	StepDeleteConfirm:    "delete_confirm",
+	StepDeleteFinish:     "delete_finish",
+	StepAddFinish:        "add_finish",
+	StepSelectPage:       "select_page",
+	StepSearchStart:      "search_start",
+	StepSearch:           "search",
+	StepListSort:         "list_sort",
+	StepListFilter:       "list_filter",
+	StepUndo:             "undo",
+	StepBroadcastConfirm: "broadcast_confirm",
+	StepBroadcastSend:    "broadcast_send",
+	StepBroadcastCancel:  "broadcast_cancel",
+}
+
+func (s Step) String() string {
+	if s >= 0 && int(s) < len(stepNames) {
+		return stepNames[s]
+	}
+	return fmt.Sprintf("step(%d)", int(s))
+}
+
 type State struct {
 	BackHandler TypeHandler
 	BackStep    Step
@@ -63,6 +136,10 @@ type State struct {
 
 	TempDebt *model.Debt
 	TempDate *time.Time
+
+	Query string // search query of the select screen
+
+	Broadcast string // text of the admin broadcast waiting for confirmation
 }
 
 func ExtractState(session *session.Session) (*State, error) {
@@ -76,33 +153,3 @@ func ExtractState(session *session.Session) (*State, error) {
 
 	return nil, fmt.Errorf("failed to extract state: expected State or *State, got %T", session.State)
 }
-
-type CallBack struct {
-	Handler TypeHandler `json:"h"`
-	Step    Step        `json:"s"`
-	Data    string      `json:"d"`
-}
-
-// CreateCallBack — serializes callback data into compact JSON array
-func CreateCallBack(handler TypeHandler, step Step, data string) (string, error) {
-	cb := CallBack{
-		Handler: handler,
-		Step:    step,
-		Data:    data,
-	}
-	jsonData, err := json.Marshal(cb)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal callback: %w", err)
-	}
-
-	return string(jsonData), nil
-}
-
-// ParseCallBack — deserializes callback data
-func ParseCallBack(data string) (*CallBack, error) {
-	var cb CallBack
-	if err := json.Unmarshal([]byte(data), &cb); err != nil {
-		return nil, fmt.Errorf("invalid callback data: %w, raw: %s", err, data)
-	}
-	return &cb, nil
-}
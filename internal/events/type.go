@@ -1,6 +1,12 @@
 package events
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrPanic — the handler panicked, retrying the event would only panic again
+var ErrPanic = errors.New("handler panicked")
 
 type Fetcher interface {
 	Fetch(ctx context.Context, limit int) ([]*Event, error)
@@ -10,6 +16,11 @@ type Processor interface {
 	Process(ctx context.Context, e *Event) error
 }
 
+// DeadLetterer — keeps events that failed every attempt, so they can be replayed later
+type DeadLetterer interface {
+	DeadLetter(ctx context.Context, e *Event, cause error, attempts int) error
+}
+
 type Type int
 
 const (
@@ -18,13 +29,37 @@ const (
 	Callback
 )
 
+func (t Type) String() string {
+	switch t {
+	case Message:
+		return "message"
+	case Callback:
+		return "callback"
+	default:
+		return "unknown"
+	}
+}
+
 type Event struct {
 	Type Type
 	Text string
 	Meta *Meta
+
+	Raw          []byte // the update as received, kept for the dead letters
+	DeadLetterID int64  // set when the event is a replayed dead letter
 }
 
 type Meta struct {
-	ChatID int
-	UserID int
+	ChatID   int
+	UserID   int
+	UpdateID int
+	From     From
+}
+
+// From — the sender of the update as telegram reports it
+type From struct {
+	Username     string
+	FirstName    string
+	LastName     string
+	LanguageCode string
 }
@@ -0,0 +1,60 @@
+package locale
+
+import "strings"
+
+const (
+	DefaultLocale   = "en-US"
+	DefaultTimeZone = "UTC"
+)
+
+type guess struct {
+	locale   string
+	timeZone string
+}
+
+// guesses — language of the telegram client -> locale and the time zone where most of its speakers live.
+// Languages spoken all over the world keep UTC.
+var guesses = map[string]guess{
+	"en":      {"en-US", DefaultTimeZone},
+	"es":      {"es-ES", DefaultTimeZone},
+	"ar":      {"ar-SA", DefaultTimeZone},
+	"ru":      {"ru-RU", "Europe/Moscow"},
+	"uk":      {"uk-UA", "Europe/Kyiv"},
+	"be":      {"be-BY", "Europe/Minsk"},
+	"kk":      {"kk-KZ", "Asia/Almaty"},
+	"uz":      {"uz-UZ", "Asia/Tashkent"},
+	"de":      {"de-DE", "Europe/Berlin"},
+	"fr":      {"fr-FR", "Europe/Paris"},
+	"it":      {"it-IT", "Europe/Rome"},
+	"nl":      {"nl-NL", "Europe/Amsterdam"},
+	"pl":      {"pl-PL", "Europe/Warsaw"},
+	"pt":      {"pt-PT", "Europe/Lisbon"},
+	"pt-br":   {"pt-BR", "America/Sao_Paulo"},
+	"tr":      {"tr-TR", "Europe/Istanbul"},
+	"he":      {"he-IL", "Asia/Jerusalem"},
+	"fa":      {"fa-IR", "Asia/Tehran"},
+	"hi":      {"hi-IN", "Asia/Kolkata"},
+	"id":      {"id-ID", "Asia/Jakarta"},
+	"ja":      {"ja-JP", "Asia/Tokyo"},
+	"ko":      {"ko-KR", "Asia/Seoul"},
+	"zh":      {"zh-CN", "Asia/Shanghai"},
+	"zh-hans": {"zh-CN", "Asia/Shanghai"},
+	"zh-hant": {"zh-TW", "Asia/Taipei"},
+}
+
+// Guess — default locale and time zone for the language_code of the telegram client.
+// Only a guess: the language says nothing certain about where the user lives.
+func Guess(languageCode string) (locale, timeZone string) {
+	tag := strings.ToLower(strings.TrimSpace(languageCode))
+
+	if g, ok := guesses[tag]; ok {
+		return g.locale, g.timeZone
+	}
+
+	primary, _, _ := strings.Cut(tag, "-")
+	if g, ok := guesses[primary]; ok {
+		return g.locale, g.timeZone
+	}
+
+	return DefaultLocale, DefaultTimeZone
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const namespace = "drillcore"
+
+var (
+	UpdatesFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_fetched_total",
+		Help:      "Telegram updates fetched by getUpdates, by event type.",
+	}, []string{"type"})
+
+	UpdatesProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_processed_total",
+		Help:      "Processed events by event type and result (ok/error).",
+	}, []string{"type", "result"})
+
+	HandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Latency of handling one event, by handler and step.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"handler", "step"})
+
+	TelegramRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_requests_total",
+		Help:      "Telegram Bot API calls by method.",
+	}, []string{"method"})
+
+	TelegramErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_errors_total",
+		Help:      "Failed Telegram Bot API calls by method.",
+	}, []string{"method"})
+
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_query_duration_seconds",
+		Help:      "Latency of storage calls by operation and result (ok/error).",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "result"})
+
+	PollingOffset = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "polling_offset",
+		Help:      "Offset the next getUpdates call starts from.",
+	})
+
+	HandlerPanics = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_panics_total",
+		Help:      "Panics recovered in the handler manager.",
+	})
+
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_events_total",
+		Help:      "Events of throttled users, by decision (notify/drop).",
+	}, []string{"decision"})
+
+	AccessDenied = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "access_denied_total",
+		Help:      "Events rejected by the access control, by reason (banned/not_allowed).",
+	}, []string{"reason"})
+
+	BroadcastDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "broadcast_deliveries_total",
+		Help:      "Broadcast delivery attempts by resulting status (sent/pending/failed/blocked/skipped).",
+	}, []string{"status"})
+
+	DeadLetters = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_letters_total",
+		Help:      "Updates moved to dead letters after every processing attempt failed.",
+	})
+)
+
+// ObserveRateLimited — gauge of the users tracked by the flood limiter
+func ObserveRateLimited(n func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rate_limiter_users",
+		Help:      "Users with events inside the rate limit window.",
+	}, func() float64 { return float64(n()) })
+}
+
+// ObserveSessions — exposes the number of active sessions, n is called on every scrape
+func ObserveSessions(n func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Users with an active session.",
+	}, func() float64 {
+		return float64(n())
+	})
+}
+
+// ErrorCounts — error counters since the process start, for the admin console
+type ErrorCounts struct {
+	Failed      int // events the handlers returned an error for
+	DeadLetters int
+	Panics      int
+	Telegram    int // failed Bot API calls
+}
+
+// Errors — current values of the error counters
+func Errors() ErrorCounts {
+	return ErrorCounts{
+		Failed:      count(UpdatesProcessed, "result", "error"),
+		DeadLetters: count(DeadLetters),
+		Panics:      count(HandlerPanics),
+		Telegram:    count(TelegramErrors),
+	}
+}
+
+// count — sum of the counter series of c, label narrows it down to the series with that label value
+func count(c prometheus.Collector, label ...string) int {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	var total float64
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil || pb.GetCounter() == nil {
+			continue
+		}
+
+		if len(label) == 2 && !hasLabel(&pb, label[0], label[1]) {
+			continue
+		}
+
+		total += pb.GetCounter().GetValue()
+	}
+
+	return int(total)
+}
+
+func hasLabel(m *dto.Metric, name, value string) bool {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue() == value
+		}
+	}
+
+	return false
+}
+
+// Since — seconds passed since start, for the histograms
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// Result — label value of the error
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -11,4 +11,208 @@ type Debt struct {
 	Description string     `json:"description" example:"Loan for car purchase"`
 	Amount      int64      `json:"amount" example:"1000000"`
 	ReturnDate  *time.Time `json:"return_date,omitempty" example:"2025-01-02T15:04:05Z"`
+	Version     int64      `json:"version" example:"1"`
+}
+
+// DebtPage
+// @Description One page of user debts, Cursor is the offset of the first debt on the page.
+// @Description Total and TotalAmount are calculated over all debts that match the request, not only the page.
+type DebtPage struct {
+	Debts       []*Debt `json:"debts"`
+	Cursor      int     `json:"cursor" example:"0"`
+	Limit       int     `json:"limit" example:"10"`
+	Total       int     `json:"total" example:"42"`
+	TotalAmount int64   `json:"total_amount" example:"1000000"`
+}
+
+func (p *DebtPage) HasPrev() bool {
+	return p.Cursor > 0
+}
+
+func (p *DebtPage) HasNext() bool {
+	return p.Cursor+len(p.Debts) < p.Total
+}
+
+func (p *DebtPage) PrevCursor() int {
+	return max(p.Cursor-p.Limit, 0)
+}
+
+func (p *DebtPage) NextCursor() int {
+	return p.Cursor + p.Limit
+}
+
+// Number — 1-based number of the page
+func (p *DebtPage) Number() int {
+	if p.Limit <= 0 {
+		return 1
+	}
+	return p.Cursor/p.Limit + 1
+}
+
+func (p *DebtPage) Pages() int {
+	if p.Limit <= 0 || p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.Limit - 1) / p.Limit
+}
+
+type DebtSort int
+
+const (
+	SortByDueDate DebtSort = iota
+	SortByAmount
+	SortByCreated
+	SortByName
+)
+
+type DebtFilter int
+
+const (
+	FilterAll DebtFilter = iota
+	FilterOverdue
+	FilterDueThisMonth
+	FilterNoDate
+)
+
+// ListSettings
+// @Description Per user sort and filter of the debt list, zero value is the default view.
+type ListSettings struct {
+	Sort   DebtSort   `json:"sort" example:"0"`
+	Filter DebtFilter `json:"filter" example:"0"`
+}
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+	AuditPay    AuditAction = "pay"
+	AuditUndo   AuditAction = "undo"
+)
+
+// AuditEvent
+// @Description One mutation of a debt, Before is nil for created debts and After is nil for deleted ones.
+// @Description UpdateID is the telegram update that triggered the mutation, 0 if unknown.
+type AuditEvent struct {
+	ID        int64       `json:"id" example:"1"`
+	UserID    int64       `json:"user_id" example:"1"`
+	DebtID    int64       `json:"debt_id" example:"1"`
+	Action    AuditAction `json:"action" example:"update"`
+	Before    *Debt       `json:"before,omitempty"`
+	After     *Debt       `json:"after,omitempty"`
+	UpdateID  int64       `json:"update_id,omitempty" example:"123456789"`
+	CreatedAt time.Time   `json:"created_at" example:"2025-01-02T15:04:05Z"`
+}
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterFailed DeadLetterStatus = "failed" // waiting for an admin
+	DeadLetterReplay DeadLetterStatus = "replay" // queued for the next fetch
+)
+
+// DeadLetter
+// @Description Telegram update that failed every processing attempt, Update is the raw update json.
+type DeadLetter struct {
+	ID        int64            `json:"id" example:"1"`
+	UpdateID  int64            `json:"update_id" example:"123456789"`
+	UserID    int64            `json:"user_id" example:"1"`
+	ChatID    int64            `json:"chat_id" example:"1"`
+	Update    []byte           `json:"update"`
+	Error     string           `json:"error" example:"failed to send message"`
+	Attempts  int              `json:"attempts" example:"4"`
+	Status    DeadLetterStatus `json:"status" example:"failed"`
+	CreatedAt time.Time        `json:"created_at" example:"2025-01-02T15:04:05Z"`
+	UpdatedAt time.Time        `json:"updated_at" example:"2025-01-02T15:04:05Z"`
+}
+
+// AccessRule
+// @Description Access of one user set by the admins, a ban wins over the allowlist.
+type AccessRule struct {
+	UserID    int64     `json:"user_id" example:"1"`
+	Allowed   bool      `json:"allowed" example:"true"`
+	Banned    bool      `json:"banned" example:"false"`
+	UpdatedBy int64     `json:"updated_by" example:"1"`
+	UpdatedAt time.Time `json:"updated_at" example:"2025-01-02T15:04:05Z"`
+}
+
+// DebtStats
+// @Description Totals over every user for the admins, Users are the users who ever touched the debt module.
+type DebtStats struct {
+	Users   int   `json:"users" example:"10"`
+	Debts   int   `json:"debts" example:"42"`
+	Overdue int   `json:"overdue" example:"3"`
+	Amount  int64 `json:"amount" example:"1000000"`
+}
+
+// User
+// @Description Telegram user known to the bot, registered on the first contact and updated on every event.
+// @Description Blocked is set when telegram refuses to deliver to the user.
+// @Description Locale and TimeZone are guessed from LanguageCode on the first contact.
+type User struct {
+	ID           int64     `json:"id" example:"1"`
+	ChatID       int64     `json:"chat_id" example:"1"`
+	Username     string    `json:"username,omitempty" example:"kamina"`
+	FirstName    string    `json:"first_name,omitempty" example:"Kamina"`
+	LastName     string    `json:"last_name,omitempty" example:"Gurren"`
+	LanguageCode string    `json:"language_code,omitempty" example:"ru"`
+	Locale       string    `json:"locale" example:"ru-RU"`
+	TimeZone     string    `json:"time_zone" example:"Europe/Moscow"`
+	Blocked      bool      `json:"blocked" example:"false"`
+	FirstSeen    time.Time `json:"first_seen" example:"2025-01-02T15:04:05Z"`
+	LastSeen     time.Time `json:"last_seen" example:"2025-01-02T15:04:05Z"`
+}
+
+// UserStats
+// @Description Number of the registered users and of those who blocked the bot.
+type UserStats struct {
+	Users   int `json:"users" example:"10"`
+	Blocked int `json:"blocked" example:"1"`
+}
+
+type BroadcastStatus string
+
+const (
+	BroadcastRunning BroadcastStatus = "running"
+	BroadcastDone    BroadcastStatus = "done"
+)
+
+// Broadcast
+// @Description Message of an admin to every user, the counters are calculated over its deliveries.
+type Broadcast struct {
+	ID         int64           `json:"id" example:"1"`
+	Text       string          `json:"text" example:"new drill hub is open"`
+	CreatedBy  int64           `json:"created_by" example:"1"`
+	ChatID     int64           `json:"chat_id" example:"1"`
+	Status     BroadcastStatus `json:"status" example:"running"`
+	Total      int             `json:"total" example:"10"`
+	Pending    int             `json:"pending" example:"2"`
+	Sent       int             `json:"sent" example:"6"`
+	Failed     int             `json:"failed" example:"1"`
+	Blocked    int             `json:"blocked" example:"1"`
+	Skipped    int             `json:"skipped" example:"0"`
+	CreatedAt  time.Time       `json:"created_at" example:"2025-01-02T15:04:05Z"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty" example:"2025-01-02T15:04:05Z"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"  // every attempt failed
+	DeliveryBlocked DeliveryStatus = "blocked" // the user blocked the bot
+	DeliverySkipped DeliveryStatus = "skipped" // the user lost access to the bot
+)
+
+// Delivery
+// @Description Broadcast to one user, Error is the last failed attempt.
+type Delivery struct {
+	BroadcastID int64          `json:"broadcast_id" example:"1"`
+	UserID      int64          `json:"user_id" example:"1"`
+	ChatID      int64          `json:"chat_id" example:"1"`
+	Status      DeliveryStatus `json:"status" example:"sent"`
+	Attempts    int            `json:"attempts" example:"1"`
+	Error       string         `json:"error,omitempty" example:"telegram API error"`
 }
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Decision — what to do with the event of a user
+type Decision int
+
+const (
+	Allow  Decision = iota
+	Notify          // first event over the limit, the user should be told once
+	Drop            // over the limit and already told, the event is dropped silently
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Allow:
+		return "allow"
+	case Notify:
+		return "notify"
+	default:
+		return "drop"
+	}
+}
+
+// Limiter — per-user sliding window: at most limit events in any window.
+// Dropped events do not count, so a flooding user is let in again as soon as
+// the window slides past their accepted events.
+type Limiter struct {
+	mu sync.Mutex
+
+	limit  int
+	window time.Duration
+	users  map[int]*user
+
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type user struct {
+	hits     []time.Time // accepted events inside the window, oldest first
+	notified bool
+}
+
+// New — limiter of limit events per window, limit <= 0 disables it
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		users:  make(map[int]*user),
+		now:    time.Now,
+	}
+}
+
+func (l *Limiter) Allow(userID int) Decision {
+	if l.limit <= 0 || l.window <= 0 {
+		return Allow
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	u, ok := l.users[userID]
+	if !ok {
+		u = &user{hits: make([]time.Time, 0, l.limit)}
+		l.users[userID] = u
+	}
+
+	u.hits = u.expire(now.Add(-l.window))
+
+	if len(u.hits) < l.limit {
+		u.hits = append(u.hits, now)
+		u.notified = false
+
+		return Allow
+	}
+
+	if !u.notified {
+		u.notified = true
+
+		return Notify
+	}
+
+	return Drop
+}
+
+// Len — users tracked by the limiter
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.users)
+}
+
+// sweep — forgets users without events in the window, at most once per window
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+
+	l.lastSweep = now
+	since := now.Add(-l.window)
+
+	for id, u := range l.users {
+		if len(u.expire(since)) == 0 {
+			delete(l.users, id)
+		}
+	}
+}
+
+// expire — hits after since
+func (u *user) expire(since time.Time) []time.Time {
+	i := 0
+	for i < len(u.hits) && !u.hits[i].After(since) {
+		i++
+	}
+
+	return u.hits[i:]
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// clock — manual time of the limiter
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newLimiter(limit int, window time.Duration) (*Limiter, *clock) {
+	c := &clock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	l := New(limit, window)
+	l.now = c.Now
+
+	return l, c
+}
+
+func assertDecisions(t *testing.T, l *Limiter, userID int, want ...Decision) {
+	t.Helper()
+
+	for i, w := range want {
+		if got := l.Allow(userID); got != w {
+			t.Fatalf("event %d of user %d: got %v, want %v", i+1, userID, got, w)
+		}
+	}
+}
+
+func TestAllowNotifiesOnce(t *testing.T) {
+	l, _ := newLimiter(2, time.Minute)
+
+	assertDecisions(t, l, 1, Allow, Allow, Notify, Drop, Drop)
+
+	// other users have limits of their own
+	assertDecisions(t, l, 2, Allow, Allow, Notify)
+}
+
+func TestAllowSlidingWindow(t *testing.T) {
+	l, c := newLimiter(2, time.Minute)
+
+	assertDecisions(t, l, 1, Allow)
+	c.Add(40 * time.Second)
+	assertDecisions(t, l, 1, Allow, Notify)
+
+	// the first event left the window, the second one is still in it;
+	// an accepted event resets the notice, the next flood is told again
+	c.Add(30 * time.Second)
+	assertDecisions(t, l, 1, Allow, Notify, Drop)
+
+	// dropped events do not count: the window slides past the accepted ones only
+	c.Add(30 * time.Second)
+	assertDecisions(t, l, 1, Allow, Notify, Drop)
+
+	c.Add(time.Minute)
+	assertDecisions(t, l, 1, Allow, Allow, Notify, Drop)
+}
+
+func TestAllowWindowBoundary(t *testing.T) {
+	l, c := newLimiter(1, time.Minute)
+
+	assertDecisions(t, l, 1, Allow)
+
+	// an event exactly one window old is out of it
+	c.Add(time.Minute)
+	assertDecisions(t, l, 1, Allow, Notify)
+}
+
+func TestAllowDisabled(t *testing.T) {
+	for _, l := range []*Limiter{New(0, time.Minute), New(1, 0)} {
+		assertDecisions(t, l, 1, Allow, Allow, Allow)
+
+		if n := l.Len(); n != 0 {
+			t.Fatalf("disabled limiter tracks %d users", n)
+		}
+	}
+}
+
+func TestSweep(t *testing.T) {
+	l, c := newLimiter(2, time.Minute)
+
+	assertDecisions(t, l, 1, Allow)
+	c.Add(30 * time.Second)
+	assertDecisions(t, l, 2, Allow)
+
+	if n := l.Len(); n != 2 {
+		t.Fatalf("tracking %d users, want 2", n)
+	}
+
+	// user 1 is idle for a window, user 2 still has an event in it;
+	// the sweep runs on the next event at most once per window
+	c.Add(40 * time.Second)
+	assertDecisions(t, l, 3, Allow)
+
+	if n := l.Len(); n != 2 {
+		t.Fatalf("tracking %d users after the sweep, want users 2 and 3", n)
+	}
+	if _, ok := l.users[1]; ok {
+		t.Fatal("idle user 1 is not forgotten")
+	}
+
+	// the next sweep is a window away, user 2 stays till then
+	c.Add(40 * time.Second)
+	assertDecisions(t, l, 3, Allow)
+	if n := l.Len(); n != 2 {
+		t.Fatalf("tracking %d users before the next sweep, want 2", n)
+	}
+
+	c.Add(30 * time.Second)
+	assertDecisions(t, l, 4, Allow)
+	if _, ok := l.users[2]; ok {
+		t.Fatal("idle user 2 is not forgotten")
+	}
+}
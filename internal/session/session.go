@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 )
@@ -37,6 +38,28 @@ func (m *Manager) Set(ctx context.Context, userID int, s *Session) error {
 	return nil
 }
 
+// Len — number of active sessions
+func (m *Manager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.sessions)
+}
+
+// UserIDs — users with an active session, ascending
+func (m *Manager) UserIDs() []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]int, 0, len(m.sessions))
+	for id := range m.sessions {
+		res = append(res, id)
+	}
+	slices.Sort(res)
+
+	return res
+}
+
 func (m *Manager) Delete(ctx context.Context, userID int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
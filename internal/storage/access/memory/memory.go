@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"drillCore/internal/model"
+)
+
+// AccessStorage — in-memory access rules, for demo mode
+type AccessStorage struct {
+	mu    sync.Mutex
+	rules map[int64]model.AccessRule
+	now   func() time.Time
+}
+
+func New() *AccessStorage {
+	return &AccessStorage{rules: make(map[int64]model.AccessRule), now: time.Now}
+}
+
+func (s *AccessStorage) AccessRules(_ context.Context) ([]*model.AccessRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]*model.AccessRule, 0, len(s.rules))
+	for _, r := range s.rules {
+		res = append(res, &r)
+	}
+
+	return res, nil
+}
+
+func (s *AccessStorage) SaveAccessRule(_ context.Context, rule *model.AccessRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule.UpdatedAt = s.now()
+	s.rules[rule.UserID] = *rule
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"drillCore/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// AccessStorage — access rules of the users in postgres
+type AccessStorage struct {
+	pool   *pgxpool.Pool
+	logger *zap.SugaredLogger
+}
+
+func New(pool *pgxpool.Pool, logger *zap.SugaredLogger) *AccessStorage {
+	return &AccessStorage{pool: pool, logger: logger}
+}
+
+func (s *AccessStorage) AccessRules(ctx context.Context) ([]*model.AccessRule, error) {
+	q := `SELECT user_id, allowed, banned, updated_by, updated_at FROM access_rule`
+
+	rows, err := s.pool.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access rules: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*model.AccessRule
+	for rows.Next() {
+		var r model.AccessRule
+		if err := rows.Scan(&r.UserID, &r.Allowed, &r.Banned, &r.UpdatedBy, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan access rule: %w", err)
+		}
+
+		res = append(res, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get access rules: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *AccessStorage) SaveAccessRule(ctx context.Context, rule *model.AccessRule) error {
+	q := `INSERT INTO access_rule (user_id, allowed, banned, updated_by)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET allowed = EXCLUDED.allowed, banned = EXCLUDED.banned, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		 RETURNING updated_at`
+
+	if err := s.pool.QueryRow(ctx, q, rule.UserID, rule.Allowed, rule.Banned, rule.UpdatedBy).Scan(&rule.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save access rule of user %d: %w", rule.UserID, err)
+	}
+
+	return nil
+}
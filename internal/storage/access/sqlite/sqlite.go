@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"drillCore/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// AccessStorage — access rules of the users in SQLite
+type AccessStorage struct {
+	conn   *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func New(conn *sql.DB, logger *zap.SugaredLogger) *AccessStorage {
+	return &AccessStorage{conn: conn, logger: logger}
+}
+
+func (s *AccessStorage) AccessRules(ctx context.Context) ([]*model.AccessRule, error) {
+	q := `SELECT user_id, allowed, banned, updated_by, updated_at FROM access_rule`
+
+	rows, err := s.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access rules: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*model.AccessRule
+	for rows.Next() {
+		var (
+			r         model.AccessRule
+			updatedAt int64
+		)
+
+		if err := rows.Scan(&r.UserID, &r.Allowed, &r.Banned, &r.UpdatedBy, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan access rule: %w", err)
+		}
+
+		r.UpdatedAt = time.Unix(updatedAt, 0)
+		res = append(res, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get access rules: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *AccessStorage) SaveAccessRule(ctx context.Context, rule *model.AccessRule) error {
+	q := `INSERT INTO access_rule (user_id, allowed, banned, updated_by)
+		 VALUES (?1, ?2, ?3, ?4)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET allowed = excluded.allowed, banned = excluded.banned, updated_by = excluded.updated_by, updated_at = unixepoch()
+		 RETURNING updated_at`
+
+	var updatedAt int64
+	if err := s.conn.QueryRowContext(ctx, q, rule.UserID, rule.Allowed, rule.Banned, rule.UpdatedBy).Scan(&updatedAt); err != nil {
+		return fmt.Errorf("failed to save access rule of user %d: %w", rule.UserID, err)
+	}
+
+	rule.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return nil
+}
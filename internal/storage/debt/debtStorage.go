@@ -1,9 +1,60 @@
 package debtStorage
 
 import (
+	"context"
 	"errors"
+	"time"
+
+	"drillCore/internal/model"
 )
 
 var (
-	ErrDebtNotFound = errors.New("debt not found")
+	ErrDebtNotFound  = errors.New("debt not found")
+	ErrDebtConflict  = errors.New("debt was changed concurrently")
+	ErrNothingToUndo = errors.New("nothing to undo")
 )
+
+// Storage — the contract of every debt storage backend, storagetest checks it.
+// Every call is scoped to the user: a debt or audit event of another user is reported as ErrDebtNotFound.
+type Storage interface {
+	// Save — inserts the debt together with its create audit event, returns the new ID
+	Save(ctx context.Context, debt *model.Debt) (int64, error)
+
+	// Debt — the debt of the user
+	Debt(ctx context.Context, userID, id int64) (*model.Debt, error)
+
+	// DebtsPage — page of user debts, nil settings means the default view
+	DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error)
+
+	// SearchDebts — case-insensitive substring search by description
+	SearchDebts(ctx context.Context, userID int64, query string, cursor, limit int) (*model.DebtPage, error)
+
+	// ListSettings — saved sort and filter of the debt list, defaults when the user never changed them
+	ListSettings(ctx context.Context, userID int64) (*model.ListSettings, error)
+	SaveListSettings(ctx context.Context, userID int64, settings *model.ListSettings) error
+
+	// Update — compare-and-swap on debt.Version: the debt is updated only if nobody changed it
+	// since it was read, otherwise ErrDebtConflict. On success debt.Version holds the new version.
+	Update(ctx context.Context, userID int64, debt *model.Debt) error
+
+	// Delete — deletes the debt only if its version still matches debt.Version,
+	// returns the audit event to pass to Undo
+	Delete(ctx context.Context, userID int64, debt *model.Debt) (int64, error)
+
+	// Pay — stores the debt after a payment, a fully paid debt (zero amount) is deleted,
+	// returns the audit event to pass to Undo
+	Pay(ctx context.Context, userID int64, debt *model.Debt) (int64, error)
+
+	// Undo — reverts the delete or payment recorded by the audit event, only while it is the latest
+	// change of the debt and within the window, otherwise ErrNothingToUndo. A deleted debt is recreated
+	// with its previous ID and creation time.
+	Undo(ctx context.Context, userID, eventID int64, window time.Duration) (*model.Debt, error)
+
+	// AuditTrail — the latest audit events of the user, newest first
+	AuditTrail(ctx context.Context, userID int64, limit int) ([]*model.AuditEvent, error)
+
+	// Stats — totals over every user
+	Stats(ctx context.Context) (*model.DebtStats, error)
+
+	Ping(ctx context.Context) error
+}
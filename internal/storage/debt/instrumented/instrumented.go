@@ -0,0 +1,122 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+	debtStorage "drillCore/internal/storage/debt"
+	"drillCore/internal/tracing"
+)
+
+// DebtStorage — records the latency and a span of every call of the wrapped storage
+type DebtStorage struct {
+	next debtStorage.Storage
+}
+
+func New(next debtStorage.Storage) *DebtStorage {
+	return &DebtStorage{next: next}
+}
+
+// begin — starts the span of operation, done records its latency and ends the span
+func begin(ctx context.Context, operation string) (context.Context, func(err error)) {
+	ctx, span := tracing.Start(ctx, "storage."+operation)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		metrics.StorageDuration.WithLabelValues(operation, metrics.Result(err)).Observe(metrics.Since(start))
+		tracing.End(span, err)
+	}
+}
+
+func (s *DebtStorage) Save(ctx context.Context, debt *model.Debt) (id int64, err error) {
+	ctx, done := begin(ctx, "save")
+	defer func() { done(err) }()
+
+	return s.next.Save(ctx, debt)
+}
+
+func (s *DebtStorage) Debt(ctx context.Context, userID, id int64) (d *model.Debt, err error) {
+	ctx, done := begin(ctx, "debt")
+	defer func() { done(err) }()
+
+	return s.next.Debt(ctx, userID, id)
+}
+
+func (s *DebtStorage) DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (p *model.DebtPage, err error) {
+	ctx, done := begin(ctx, "debts_page")
+	defer func() { done(err) }()
+
+	return s.next.DebtsPage(ctx, userID, cursor, limit, settings)
+}
+
+func (s *DebtStorage) SearchDebts(ctx context.Context, userID int64, query string, cursor, limit int) (p *model.DebtPage, err error) {
+	ctx, done := begin(ctx, "search_debts")
+	defer func() { done(err) }()
+
+	return s.next.SearchDebts(ctx, userID, query, cursor, limit)
+}
+
+func (s *DebtStorage) ListSettings(ctx context.Context, userID int64) (ls *model.ListSettings, err error) {
+	ctx, done := begin(ctx, "list_settings")
+	defer func() { done(err) }()
+
+	return s.next.ListSettings(ctx, userID)
+}
+
+func (s *DebtStorage) SaveListSettings(ctx context.Context, userID int64, settings *model.ListSettings) (err error) {
+	ctx, done := begin(ctx, "save_list_settings")
+	defer func() { done(err) }()
+
+	return s.next.SaveListSettings(ctx, userID, settings)
+}
+
+func (s *DebtStorage) Update(ctx context.Context, userID int64, debt *model.Debt) (err error) {
+	ctx, done := begin(ctx, "update")
+	defer func() { done(err) }()
+
+	return s.next.Update(ctx, userID, debt)
+}
+
+func (s *DebtStorage) Delete(ctx context.Context, userID int64, debt *model.Debt) (eventID int64, err error) {
+	ctx, done := begin(ctx, "delete")
+	defer func() { done(err) }()
+
+	return s.next.Delete(ctx, userID, debt)
+}
+
+func (s *DebtStorage) Pay(ctx context.Context, userID int64, debt *model.Debt) (eventID int64, err error) {
+	ctx, done := begin(ctx, "pay")
+	defer func() { done(err) }()
+
+	return s.next.Pay(ctx, userID, debt)
+}
+
+func (s *DebtStorage) Undo(ctx context.Context, userID, eventID int64, window time.Duration) (d *model.Debt, err error) {
+	ctx, done := begin(ctx, "undo")
+	defer func() { done(err) }()
+
+	return s.next.Undo(ctx, userID, eventID, window)
+}
+
+func (s *DebtStorage) AuditTrail(ctx context.Context, userID int64, limit int) (res []*model.AuditEvent, err error) {
+	ctx, done := begin(ctx, "audit_trail")
+	defer func() { done(err) }()
+
+	return s.next.AuditTrail(ctx, userID, limit)
+}
+
+func (s *DebtStorage) Stats(ctx context.Context) (st *model.DebtStats, err error) {
+	ctx, done := begin(ctx, "stats")
+	defer func() { done(err) }()
+
+	return s.next.Stats(ctx)
+}
+
+func (s *DebtStorage) Ping(ctx context.Context) (err error) {
+	ctx, done := begin(ctx, "ping")
+	defer func() { done(err) }()
+
+	return s.next.Ping(ctx)
+}
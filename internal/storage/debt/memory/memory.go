@@ -0,0 +1,475 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"drillCore/internal/events"
+	"drillCore/internal/model"
+	debtStorage "drillCore/internal/storage/debt"
+
+	"go.uber.org/zap"
+)
+
+// DebtStorage — thread-safe in-memory debtStorage.Storage with the postgres semantics, for tests and demo mode.
+// Debts are copied on the way in and out, so callers never share memory with the storage.
+type DebtStorage struct {
+	mu sync.Mutex
+
+	nextID   int64
+	debts    map[int64]*entry
+	deleted  map[int64]time.Time // creation time of removed debts, undo puts them back at their place
+	settings map[int64]model.ListSettings
+	audit    []*model.AuditEvent
+
+	logger *zap.SugaredLogger
+	now    func() time.Time
+}
+
+// errNonPositiveAmount — mirrors the CHECK (amount > 0) of the SQL backends
+var errNonPositiveAmount = errors.New("amount must be positive")
+
+type entry struct {
+	debt      model.Debt
+	createdAt time.Time
+}
+
+func New(logger *zap.SugaredLogger) *DebtStorage {
+	return &DebtStorage{
+		debts:    make(map[int64]*entry),
+		deleted:  make(map[int64]time.Time),
+		settings: make(map[int64]model.ListSettings),
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *DebtStorage) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *DebtStorage) Save(ctx context.Context, debt *model.Debt) (int64, error) {
+	if debt.Amount <= 0 {
+		return 0, fmt.Errorf("failed to add debt: %w", errNonPositiveAmount)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+
+	saved := copyDebt(debt)
+	saved.ID = s.nextID
+	saved.Version = 1
+
+	s.debts[saved.ID] = &entry{debt: *saved, createdAt: s.now()}
+	s.record(ctx, debt.UserID, saved.ID, model.AuditCreate, nil, saved)
+
+	debt.Version = saved.Version
+
+	s.logger.Debugf("successfully added debt (ID: %d) for user %d", saved.ID, debt.UserID)
+	return saved.ID, nil
+}
+
+func (s *DebtStorage) Debt(_ context.Context, userID, id int64) (*model.Debt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, err := s.get(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debt: %w", err)
+	}
+
+	return copyDebt(&e.debt), nil
+}
+
+func (s *DebtStorage) DebtsPage(_ context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error) {
+	return s.page(userID, "", cursor, limit, settings)
+}
+
+func (s *DebtStorage) SearchDebts(_ context.Context, userID int64, query string, cursor, limit int) (*model.DebtPage, error) {
+	return s.page(userID, query, cursor, limit, nil)
+}
+
+func (s *DebtStorage) page(userID int64, query string, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error) {
+	if settings == nil {
+		settings = &model.ListSettings{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	match, ok := s.filter(settings.Filter, now)
+	if !ok {
+		return nil, fmt.Errorf("failed to get debts page: unknown filter %d", settings.Filter)
+	}
+
+	compare, ok := s.order(settings.Sort, now)
+	if !ok {
+		return nil, fmt.Errorf("failed to get debts page: unknown sort %d", settings.Sort)
+	}
+
+	query = strings.ToLower(query)
+
+	matched := make([]*entry, 0)
+	for _, e := range s.debts {
+		if e.debt.UserID != userID || !match(&e.debt) {
+			continue
+		}
+
+		if query != "" && !strings.Contains(strings.ToLower(e.debt.Description), query) {
+			continue
+		}
+
+		matched = append(matched, e)
+	}
+
+	slices.SortFunc(matched, compare)
+
+	res := &model.DebtPage{
+		Debts:  make([]*model.Debt, 0, limit),
+		Cursor: cursor,
+		Limit:  limit,
+		Total:  len(matched),
+	}
+
+	for _, e := range matched {
+		res.TotalAmount += e.debt.Amount
+	}
+
+	for i := cursor; i < len(matched) && i < cursor+limit; i++ {
+		res.Debts = append(res.Debts, copyDebt(&matched[i].debt))
+	}
+
+	return res, nil
+}
+
+// filter — same conditions as the whitelisted postgres filters
+func (s *DebtStorage) filter(f model.DebtFilter, now time.Time) (func(d *model.Debt) bool, bool) {
+	switch f {
+	case model.FilterAll:
+		return func(*model.Debt) bool { return true }, true
+
+	case model.FilterOverdue:
+		return func(d *model.Debt) bool {
+			return d.ReturnDate != nil && d.ReturnDate.Before(now)
+		}, true
+
+	case model.FilterDueThisMonth:
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		to := from.AddDate(0, 1, 0)
+
+		return func(d *model.Debt) bool {
+			return d.ReturnDate != nil && !d.ReturnDate.Before(from) && d.ReturnDate.Before(to)
+		}, true
+
+	case model.FilterNoDate:
+		return func(d *model.Debt) bool { return d.ReturnDate == nil }, true
+
+	default:
+		return nil, false
+	}
+}
+
+// order — same ordering as the whitelisted postgres ORDER BY clauses
+func (s *DebtStorage) order(sort model.DebtSort, now time.Time) (func(a, b *entry) int, bool) {
+	switch sort {
+	case model.SortByDueDate:
+		// upcoming debts first, then overdue ones, debts without return date go last
+		group := func(d *model.Debt) int {
+			switch {
+			case d.ReturnDate == nil:
+				return 2
+			case d.ReturnDate.Before(now):
+				return 1
+			default:
+				return 0
+			}
+		}
+
+		return func(a, b *entry) int {
+			if c := cmp.Compare(group(&a.debt), group(&b.debt)); c != 0 {
+				return c
+			}
+
+			if a.debt.ReturnDate != nil && b.debt.ReturnDate != nil {
+				if c := a.debt.ReturnDate.Compare(*b.debt.ReturnDate); c != 0 {
+					return c
+				}
+			}
+
+			return cmp.Compare(a.debt.ID, b.debt.ID)
+		}, true
+
+	case model.SortByAmount:
+		return func(a, b *entry) int {
+			return cmp.Or(cmp.Compare(b.debt.Amount, a.debt.Amount), cmp.Compare(a.debt.ID, b.debt.ID))
+		}, true
+
+	case model.SortByCreated:
+		return func(a, b *entry) int {
+			return cmp.Or(b.createdAt.Compare(a.createdAt), cmp.Compare(b.debt.ID, a.debt.ID))
+		}, true
+
+	case model.SortByName:
+		return func(a, b *entry) int {
+			return cmp.Or(
+				strings.Compare(strings.ToLower(a.debt.Description), strings.ToLower(b.debt.Description)),
+				cmp.Compare(a.debt.ID, b.debt.ID),
+			)
+		}, true
+
+	default:
+		return nil, false
+	}
+}
+
+func (s *DebtStorage) ListSettings(_ context.Context, userID int64) (*model.ListSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := s.settings[userID]
+	return &res, nil
+}
+
+func (s *DebtStorage) SaveListSettings(_ context.Context, userID int64, settings *model.ListSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.settings[userID] = *settings
+
+	s.logger.Debugf("successfully saved list settings for user %d: %+v", userID, settings)
+	return nil
+}
+
+func (s *DebtStorage) Update(ctx context.Context, userID int64, debt *model.Debt) error {
+	if _, err := s.change(ctx, userID, debt, model.AuditUpdate); err != nil {
+		return fmt.Errorf("failed to update debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully updated debt (ID: %d, version: %d)", debt.ID, debt.Version)
+	return nil
+}
+
+func (s *DebtStorage) Delete(ctx context.Context, userID int64, debt *model.Debt) (int64, error) {
+	eventID, err := s.change(ctx, userID, debt, model.AuditDelete)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully deleted debt (ID: %d)", debt.ID)
+	return eventID, nil
+}
+
+func (s *DebtStorage) Pay(ctx context.Context, userID int64, debt *model.Debt) (int64, error) {
+	eventID, err := s.change(ctx, userID, debt, model.AuditPay)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pay debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully paid debt (ID: %d), left: %d", debt.ID, debt.Amount)
+	return eventID, nil
+}
+
+func (s *DebtStorage) change(ctx context.Context, userID int64, debt *model.Debt, action model.AuditAction) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, err := s.get(userID, debt.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if e.debt.Version != debt.Version {
+		return 0, debtStorage.ErrDebtConflict
+	}
+
+	before := copyDebt(&e.debt)
+
+	if action == model.AuditDelete || (action == model.AuditPay && debt.Amount == 0) {
+		delete(s.debts, debt.ID)
+		s.deleted[debt.ID] = e.createdAt
+
+		return s.record(ctx, userID, debt.ID, action, before, nil), nil
+	}
+
+	if debt.Amount <= 0 {
+		return 0, errNonPositiveAmount
+	}
+
+	after := s.update(e, debt)
+	eventID := s.record(ctx, userID, debt.ID, action, before, after)
+
+	debt.Version = after.Version
+	return eventID, nil
+}
+
+// update — stores the editable fields of debt into e and bumps the version
+func (s *DebtStorage) update(e *entry, debt *model.Debt) *model.Debt {
+	e.debt.Description = debt.Description
+	e.debt.Amount = debt.Amount
+	e.debt.ReturnDate = copyDebt(debt).ReturnDate
+	e.debt.Version++
+
+	return copyDebt(&e.debt)
+}
+
+func (s *DebtStorage) Undo(ctx context.Context, userID, eventID int64, window time.Duration) (*model.Debt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ev := s.event(userID, eventID)
+	if ev == nil {
+		return nil, fmt.Errorf("failed to undo debt change: %w", debtStorage.ErrDebtNotFound)
+	}
+
+	debtID := ev.DebtID
+	if ev != s.last(userID, debtID) || s.now().Sub(ev.CreatedAt) > window ||
+		(ev.Action != model.AuditDelete && ev.Action != model.AuditPay) {
+		return nil, fmt.Errorf("failed to undo debt change: %w", debtStorage.ErrNothingToUndo)
+	}
+
+	var restored *model.Debt
+	if ev.After == nil {
+		restored = copyDebt(ev.Before)
+		restored.Version++
+
+		s.debts[debtID] = &entry{debt: *restored, createdAt: s.deleted[debtID]}
+		delete(s.deleted, debtID)
+	} else {
+		e, err := s.get(userID, debtID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to undo debt change: %w", err)
+		}
+
+		if e.debt.Version != ev.After.Version {
+			return nil, fmt.Errorf("failed to undo debt change: %w", debtStorage.ErrDebtConflict)
+		}
+
+		restored = s.update(e, ev.Before)
+	}
+
+	s.record(ctx, userID, debtID, model.AuditUndo, ev.After, restored)
+
+	s.logger.Debugf("successfully undone change of debt (ID: %d, event: %d)", debtID, eventID)
+	return copyDebt(restored), nil
+}
+
+func (s *DebtStorage) AuditTrail(_ context.Context, userID int64, limit int) ([]*model.AuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]*model.AuditEvent, 0, limit)
+	for i := len(s.audit) - 1; i >= 0 && len(res) < limit; i-- {
+		if e := s.audit[i]; e.UserID == userID {
+			c := *e
+			c.Before, c.After = copyDebt(e.Before), copyDebt(e.After)
+			res = append(res, &c)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *DebtStorage) Stats(_ context.Context) (*model.DebtStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	st := model.DebtStats{Users: len(s.users()), Debts: len(s.debts)}
+	for _, e := range s.debts {
+		st.Amount += e.debt.Amount
+		if e.debt.ReturnDate != nil && e.debt.ReturnDate.Before(now) {
+			st.Overdue++
+		}
+	}
+
+	return &st, nil
+}
+
+// users — everyone who ever touched the debt module, paid debts are gone but their audit stays
+func (s *DebtStorage) users() map[int64]struct{} {
+	res := make(map[int64]struct{})
+	for _, e := range s.debts {
+		res[e.debt.UserID] = struct{}{}
+	}
+	for _, e := range s.audit {
+		res[e.UserID] = struct{}{}
+	}
+	for id := range s.settings {
+		res[id] = struct{}{}
+	}
+
+	return res
+}
+
+func (s *DebtStorage) get(userID, id int64) (*entry, error) {
+	e, ok := s.debts[id]
+	if !ok || e.debt.UserID != userID {
+		return nil, debtStorage.ErrDebtNotFound
+	}
+
+	return e, nil
+}
+
+// event — audit event of the user, nil for a foreign or unknown one
+func (s *DebtStorage) event(userID, eventID int64) *model.AuditEvent {
+	if eventID < 1 || eventID > int64(len(s.audit)) {
+		return nil
+	}
+
+	if e := s.audit[eventID-1]; e.UserID == userID {
+		return e
+	}
+
+	return nil
+}
+
+func (s *DebtStorage) last(userID, debtID int64) *model.AuditEvent {
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if e := s.audit[i]; e.DebtID == debtID && e.UserID == userID {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// record — appends the audit event and returns its ID, the ID is the position in s.audit plus one
+func (s *DebtStorage) record(ctx context.Context, userID, debtID int64, action model.AuditAction, before, after *model.Debt) int64 {
+	id := int64(len(s.audit) + 1)
+	s.audit = append(s.audit, &model.AuditEvent{
+		ID:        id,
+		UserID:    userID,
+		DebtID:    debtID,
+		Action:    action,
+		Before:    copyDebt(before),
+		After:     copyDebt(after),
+		UpdateID:  int64(events.UpdateID(ctx)),
+		CreatedAt: s.now(),
+	})
+
+	return id
+}
+
+func copyDebt(d *model.Debt) *model.Debt {
+	if d == nil {
+		return nil
+	}
+
+	c := *d
+	if d.ReturnDate != nil {
+		t := *d.ReturnDate
+		c.ReturnDate = &t
+	}
+
+	return &c
+}
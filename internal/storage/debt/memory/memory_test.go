@@ -0,0 +1,19 @@
+package memory_test
+
+import (
+	"testing"
+
+	debtStorage "drillCore/internal/storage/debt"
+	"drillCore/internal/storage/debt/memory"
+	"drillCore/internal/storage/debt/storagetest"
+
+	"go.uber.org/zap"
+)
+
+func newStorage(*testing.T) debtStorage.Storage {
+	return memory.New(zap.NewNop().Sugar())
+}
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, newStorage)
+}
@@ -3,159 +3,361 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"drillCore/internal/config"
 	"drillCore/internal/model"
 	debtStorage "drillCore/internal/storage/debt"
+	"drillCore/internal/storage/pg"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
+	"time"
 )
 
+// DebtStorage — debtStorage.Storage on postgres, Update and Delete lock the row till the end of the transaction
 type DebtStorage struct {
-	db     *pgx.Conn
+	pool   *pgxpool.Pool
+	db     pg.Querier // pool, or the transaction inside WithTx
 	logger *zap.SugaredLogger
 }
 
-func New(ctx context.Context, cfg *config.DbEnvs, logger *zap.SugaredLogger) (*DebtStorage, error) {
-	conn, err := pgx.Connect(ctx, fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Host, cfg.Port, cfg.User, cfg.Pass, cfg.Name))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
+func New(pool *pgxpool.Pool, logger *zap.SugaredLogger) *DebtStorage {
+	return &DebtStorage{pool: pool, db: pool, logger: logger}
+}
 
-	if err := conn.Ping(ctx); err != nil {
-		defer func() { _ = conn.Close(ctx) }()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+func (s *DebtStorage) Ping(ctx context.Context) error {
+	if err := s.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
 	}
-
-	return &DebtStorage{db: conn, logger: logger}, nil
+	return nil
 }
 
-func (s *DebtStorage) Close(ctx context.Context) error {
-	if err := s.db.Close(ctx); err != nil {
-		return fmt.Errorf("failed to close database connection: %w", err)
+// WithTx — unit of work: every call on tx runs in one transaction, which is committed
+// when fn returns nil. Nested calls join the outer transaction.
+func (s *DebtStorage) WithTx(ctx context.Context, fn func(tx *DebtStorage) error) error {
+	if _, ok := s.db.(pgx.Tx); ok {
+		return fn(s)
 	}
-	return nil
+
+	return pg.WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		return fn(&DebtStorage{pool: s.pool, db: tx, logger: s.logger})
+	})
 }
 
 func (s *DebtStorage) Save(ctx context.Context, debt *model.Debt) (int64, error) {
 	q := `INSERT INTO debt (user_id, description, amount, return_date)
 		 VALUES ($1, $2, $3, $4)
-		 RETURNING id`
+		 RETURNING id, version`
 
-	var debtID int64
-	err := s.db.QueryRow(ctx, q,
-		debt.UserID,
-		debt.Description,
-		debt.Amount,
-		debt.ReturnDate,
-	).Scan(&debtID)
+	saved := *debt
+	err := s.WithTx(ctx, func(tx *DebtStorage) error {
+		err := tx.db.QueryRow(ctx, q,
+			debt.UserID,
+			debt.Description,
+			debt.Amount,
+			debt.ReturnDate,
+		).Scan(&saved.ID, &saved.Version)
+		if err != nil {
+			return fmt.Errorf("failed to insert debt: %w", err)
+		}
+
+		_, err = tx.audit(ctx, debt.UserID, saved.ID, model.AuditCreate, nil, &saved, nil)
+		return err
+	})
 	if err != nil {
-		return -1, fmt.Errorf("failed to insert debt: %w", err)
+		return -1, err
 	}
 
-	s.logger.Debugf("successfully added debt (ID: %d) for user %d", debtID, debt.UserID)
-	return debtID, nil
+	debt.Version = saved.Version
+
+	s.logger.Debugf("successfully added debt (ID: %d) for user %d", saved.ID, debt.UserID)
+	return saved.ID, nil
 }
 
-func (s *DebtStorage) Debt(ctx context.Context, id int64) (*model.Debt, error) {
-	q := `SELECT id, user_id, description, amount, return_date FROM debt WHERE id = $1`
+func (s *DebtStorage) Debt(ctx context.Context, userID, id int64) (*model.Debt, error) {
+	q := `SELECT id, user_id, description, amount, return_date, version FROM debt WHERE id = $1 AND user_id = $2`
 
-	var d model.Debt
-	var date sql.NullTime
-	err := s.db.QueryRow(ctx, q, id).Scan(&d.ID, &d.UserID, &d.Description, &d.Amount, &date)
+	d, err := scanDebt(s.db.QueryRow(ctx, q, id, userID))
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, debtStorage.ErrDebtNotFound
-		}
 		return nil, fmt.Errorf("failed to get debt: %w", err)
 	}
 
-	if date.Valid {
-		d.ReturnDate = &date.Time
+	return d, nil
+}
+
+var (
+	// debtsOrder — whitelisted ORDER BY clauses, never build them from user input
+	debtsOrder = map[model.DebtSort]string{
+		// upcoming debts first, then overdue ones, debts without return date go last
+		model.SortByDueDate: `ORDER BY
+			CASE
+				WHEN return_date IS NULL THEN 2
+				WHEN return_date < NOW() THEN 1
+				ELSE 0
+			END,
+			return_date,
+			id`,
+		model.SortByAmount:  `ORDER BY amount DESC, id`,
+		model.SortByCreated: `ORDER BY created_at DESC, id DESC`,
+		model.SortByName:    `ORDER BY lower(description), id`,
 	}
-	return &d, nil
+
+	// debtsFilter — whitelisted conditions appended to WHERE
+	debtsFilter = map[model.DebtFilter]string{
+		model.FilterAll:     ``,
+		model.FilterOverdue: ` AND return_date < NOW()`,
+		model.FilterDueThisMonth: ` AND return_date >= date_trunc('month', NOW())
+			AND return_date < date_trunc('month', NOW()) + INTERVAL '1 month'`,
+		model.FilterNoDate: ` AND return_date IS NULL`,
+	}
+)
+
+func (s *DebtStorage) DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error) {
+	return s.page(ctx, userID, "", cursor, limit, settings)
+}
+
+func (s *DebtStorage) SearchDebts(ctx context.Context, userID int64, query string, cursor, limit int) (*model.DebtPage, error) {
+	return s.page(ctx, userID, query, cursor, limit, nil)
 }
 
-func (s *DebtStorage) Debts(ctx context.Context, userID int64) ([]*model.Debt, error) {
-	q := `SELECT id, user_id, description, amount, return_date
-		 FROM debt WHERE user_id = $1`
+func (s *DebtStorage) page(ctx context.Context, userID int64, query string, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error) {
+	if settings == nil {
+		settings = &model.ListSettings{}
+	}
+
+	order, ok := debtsOrder[settings.Sort]
+	if !ok {
+		return nil, fmt.Errorf("failed to get debts page: unknown sort %d", settings.Sort)
+	}
+
+	cond, ok := debtsFilter[settings.Filter]
+	if !ok {
+		return nil, fmt.Errorf("failed to get debts page: unknown filter %d", settings.Filter)
+	}
+
+	filter := `WHERE user_id = $1 AND ($2 = '' OR strpos(lower(description), lower($2)) > 0)` + cond
 
-	row, err := s.db.Query(ctx, q, userID)
+	res := &model.DebtPage{
+		Debts:  make([]*model.Debt, 0, limit),
+		Cursor: cursor,
+		Limit:  limit,
+	}
+
+	q := `SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM debt ` + filter
+
+	err := s.db.QueryRow(ctx, q, userID, query).Scan(&res.Total, &res.TotalAmount)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("failed to find debt: %w", debtStorage.ErrDebtNotFound)
-		}
-		return nil, fmt.Errorf("failed to get debt: %w", err)
+		return nil, fmt.Errorf("failed to count debts: %w", err)
+	}
+
+	if res.Total == 0 {
+		return res, nil
 	}
 
-	res := make([]*model.Debt, 0)
-	for row.Next() {
+	q = `SELECT id, user_id, description, amount, return_date, version
+		 FROM debt ` + filter + `
+		 ` + order + `
+		 LIMIT $3 OFFSET $4`
+
+	rows, err := s.db.Query(ctx, q, userID, query, limit, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debts page: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
 		var debt model.Debt
 		var date sql.NullTime
 
-		err = row.Scan(
+		err = rows.Scan(
 			&debt.ID,
 			&debt.UserID,
 			&debt.Description,
 			&debt.Amount,
 			&date,
+			&debt.Version,
 		)
 		if err != nil {
-			s.logger.Warnw("failed to scan debt", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan debt: %w", err)
 		}
 
 		if date.Valid {
 			debt.ReturnDate = &date.Time
 		}
 
-		res = append(res, &debt)
+		res.Debts = append(res.Debts, &debt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read debts page: %w", err)
 	}
 
 	return res, nil
 }
 
-func (s *DebtStorage) Update(ctx context.Context, debt *model.Debt) error {
-	q := `UPDATE debt 
-		 SET user_id = $1, 
-		     description = $2, 
-		     amount = $3, 
-		     return_date = $4 
-		 WHERE id = $5`
+func (s *DebtStorage) ListSettings(ctx context.Context, userID int64) (*model.ListSettings, error) {
+	q := `SELECT sort, filter FROM debt_list_settings WHERE user_id = $1`
 
-	result, err := s.db.Exec(ctx, q,
-		debt.UserID,
+	var res model.ListSettings
+	err := s.db.QueryRow(ctx, q, userID).Scan(&res.Sort, &res.Filter)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.ListSettings{}, nil
+		}
+		return nil, fmt.Errorf("failed to get list settings: %w", err)
+	}
+
+	return &res, nil
+}
+
+func (s *DebtStorage) SaveListSettings(ctx context.Context, userID int64, settings *model.ListSettings) error {
+	q := `INSERT INTO debt_list_settings (user_id, sort, filter, updated_at)
+		 VALUES ($1, $2, $3, NOW())
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET sort = EXCLUDED.sort, filter = EXCLUDED.filter, updated_at = EXCLUDED.updated_at`
+
+	_, err := s.db.Exec(ctx, q, userID, settings.Sort, settings.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to save list settings: %w", err)
+	}
+
+	s.logger.Debugf("successfully saved list settings for user %d: %+v", userID, settings)
+	return nil
+}
+
+func (s *DebtStorage) Update(ctx context.Context, userID int64, debt *model.Debt) error {
+	if _, err := s.change(ctx, userID, debt, model.AuditUpdate); err != nil {
+		return fmt.Errorf("failed to update debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully updated debt (ID: %d, version: %d)", debt.ID, debt.Version)
+	return nil
+}
+
+func (s *DebtStorage) Delete(ctx context.Context, userID int64, debt *model.Debt) (int64, error) {
+	eventID, err := s.change(ctx, userID, debt, model.AuditDelete)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully deleted debt (ID: %d)", debt.ID)
+	return eventID, nil
+}
+
+func (s *DebtStorage) Pay(ctx context.Context, userID int64, debt *model.Debt) (int64, error) {
+	eventID, err := s.change(ctx, userID, debt, model.AuditPay)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pay debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully paid debt (ID: %d), left: %d", debt.ID, debt.Amount)
+	return eventID, nil
+}
+
+// change — versioned update or delete of the debt together with its audit event, returns the event ID
+func (s *DebtStorage) change(ctx context.Context, userID int64, debt *model.Debt, action model.AuditAction) (int64, error) {
+	remove := action == model.AuditDelete || (action == model.AuditPay && debt.Amount == 0)
+
+	var (
+		after     *model.Debt
+		createdAt *time.Time
+		eventID   int64
+	)
+	err := s.WithTx(ctx, func(tx *DebtStorage) error {
+		before, err := tx.lock(ctx, userID, debt)
+		if err != nil {
+			return err
+		}
+
+		if !remove {
+			after, err = tx.update(ctx, debt)
+			if err != nil {
+				return err
+			}
+		} else if createdAt, err = tx.delete(ctx, debt.ID); err != nil {
+			return err
+		}
+
+		eventID, err = tx.audit(ctx, userID, debt.ID, action, before, after, createdAt)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if after != nil {
+		debt.Version = after.Version
+	}
+	return eventID, nil
+}
+
+// lock — locks the debt of the user till the end of the transaction, ErrDebtConflict
+// means it was changed since debt was read
+func (s *DebtStorage) lock(ctx context.Context, userID int64, debt *model.Debt) (*model.Debt, error) {
+	q := `SELECT id, user_id, description, amount, return_date, version
+		 FROM debt WHERE id = $1 AND user_id = $2
+		 FOR UPDATE`
+
+	cur, err := scanDebt(s.db.QueryRow(ctx, q, debt.ID, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	if cur.Version != debt.Version {
+		return nil, debtStorage.ErrDebtConflict
+	}
+
+	return cur, nil
+}
+
+func (s *DebtStorage) update(ctx context.Context, debt *model.Debt) (*model.Debt, error) {
+	q := `UPDATE debt
+		 SET description = $1,
+		     amount = $2,
+		     return_date = $3,
+		     version = version + 1,
+		     updated_at = NOW()
+		 WHERE id = $4
+		 RETURNING version`
+
+	after := *debt
+	err := s.db.QueryRow(ctx, q,
 		debt.Description,
 		debt.Amount,
 		debt.ReturnDate,
 		debt.ID,
-	)
+	).Scan(&after.Version)
 	if err != nil {
-		return fmt.Errorf("failed to update debt: %w", err)
+		return nil, err
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("failed to update debt: %w", debtStorage.ErrDebtNotFound)
+	return &after, nil
+}
+
+// delete — removes the debt and returns its creation time for the audit snapshot
+func (s *DebtStorage) delete(ctx context.Context, id int64) (*time.Time, error) {
+	var createdAt time.Time
+	if err := s.db.QueryRow(ctx, "DELETE FROM debt WHERE id = $1 RETURNING created_at", id).Scan(&createdAt); err != nil {
+		return nil, err
 	}
 
-	s.logger.Debugf("successfully updated debt (ID: %d)", debt.ID)
-	return nil
+	return &createdAt, nil
 }
 
-func (s *DebtStorage) Delete(ctx context.Context, id int64) error {
-	q := "DELETE FROM debt WHERE id = $1"
+func scanDebt(row pgx.Row) (*model.Debt, error) {
+	var d model.Debt
+	var date sql.NullTime
 
-	result, err := s.db.Exec(ctx, q, id)
+	err := row.Scan(&d.ID, &d.UserID, &d.Description, &d.Amount, &date, &d.Version)
 	if err != nil {
-		return fmt.Errorf("failed to delete debt: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, debtStorage.ErrDebtNotFound
+		}
+		return nil, err
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("failed to update debt: %w", debtStorage.ErrDebtNotFound)
+	if date.Valid {
+		d.ReturnDate = &date.Time
 	}
-
-	s.logger.Debugf("successfully deleted debt (ID: %d)", id)
-	return nil
+	return &d, nil
 }
@@ -0,0 +1,54 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+
+	debtStorage "drillCore/internal/storage/debt"
+	"drillCore/internal/storage/debt/postgres"
+	"drillCore/internal/storage/debt/storagetest"
+	"drillCore/internal/storage/migrate"
+	"drillCore/migrations"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// dsnEnv — connection string of a disposable database, the tests are skipped without it
+const dsnEnv = "TEST_POSTGRES_DSN"
+
+func TestStorage(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+
+	logger := zap.NewNop().Sugar()
+
+	pool, err := pgxpool.New(t.Context(), dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	m, err := migrate.New(pool, migrations.FS, logger)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+
+	if err := m.Up(t.Context()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// every test starts from empty tables
+	newStorage := func(t *testing.T) debtStorage.Storage {
+		_, err := pool.Exec(t.Context(), `TRUNCATE debt, audit_event, debt_list_settings RESTART IDENTITY`)
+		if err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+
+		return postgres.New(pool, logger)
+	}
+
+	storagetest.Run(t, newStorage)
+}
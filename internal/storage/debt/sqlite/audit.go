@@ -0,0 +1,216 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"drillCore/internal/events"
+	"drillCore/internal/model"
+	debtStorage "drillCore/internal/storage/debt"
+)
+
+// audit — writes the audit event and returns its ID, must run inside the transaction of the mutation.
+// createdAt of a removed debt is kept in the before snapshot, so undo puts it back at its place in the list.
+func (s *DebtStorage) audit(ctx context.Context, userID, debtID int64, action model.AuditAction, before, after *model.Debt, createdAt *time.Time) (int64, error) {
+	q := `INSERT INTO audit_event (user_id, debt_id, action, before, after, update_id)
+		 VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		 RETURNING id`
+
+	b, err := snapshot(before, createdAt)
+	if err != nil {
+		return 0, err
+	}
+
+	a, err := snapshot(after, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var updateID *int64
+	if id := int64(events.UpdateID(ctx)); id != 0 {
+		updateID = &id
+	}
+
+	var id int64
+	err = s.db.QueryRowContext(ctx, q, userID, debtID, string(action), b, a, updateID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write audit event: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *DebtStorage) AuditTrail(ctx context.Context, userID int64, limit int) ([]*model.AuditEvent, error) {
+	q := `SELECT id, user_id, debt_id, action, before, after, COALESCE(update_id, 0), created_at
+		 FROM audit_event WHERE user_id = ?1
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT ?2`
+
+	rows, err := s.db.QueryContext(ctx, q, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]*model.AuditEvent, 0, limit)
+	for rows.Next() {
+		var e model.AuditEvent
+		var action string
+		var before, after sql.NullString
+		var createdAt int64
+
+		err = rows.Scan(&e.ID, &e.UserID, &e.DebtID, &action, &before, &after, &e.UpdateID, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+
+		e.Action = model.AuditAction(action)
+		e.CreatedAt = time.Unix(createdAt, 0)
+
+		if e.Before, err = restore(before); err != nil {
+			return nil, err
+		}
+
+		if e.After, err = restore(after); err != nil {
+			return nil, err
+		}
+
+		res = append(res, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit trail: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *DebtStorage) Undo(ctx context.Context, userID, eventID int64, window time.Duration) (*model.Debt, error) {
+	q := `SELECT debt_id, action, before, after, unixepoch() - created_at <= ?3,
+		        id = (SELECT MAX(id) FROM audit_event latest WHERE latest.debt_id = audit_event.debt_id)
+		 FROM audit_event WHERE id = ?1 AND user_id = ?2`
+
+	var restored *model.Debt
+	err := s.WithTx(ctx, func(tx *DebtStorage) error {
+		var debtID int64
+		var action string
+		var before, after sql.NullString
+		var inWindow, isLatest bool
+		err := tx.db.QueryRowContext(ctx, q, eventID, userID, int64(window.Seconds())).
+			Scan(&debtID, &action, &before, &after, &inWindow, &isLatest)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return debtStorage.ErrDebtNotFound
+			}
+			return err
+		}
+
+		act := model.AuditAction(action)
+		if !inWindow || !isLatest || (act != model.AuditDelete && act != model.AuditPay) {
+			return debtStorage.ErrNothingToUndo
+		}
+
+		prev, createdAt, err := restoreSnapshot(before)
+		if err != nil {
+			return err
+		}
+
+		cur, err := restore(after)
+		if err != nil {
+			return err
+		}
+
+		if cur == nil {
+			restored, err = tx.recreate(ctx, prev, createdAt)
+		} else {
+			if _, err = tx.current(ctx, userID, cur); err != nil {
+				return err
+			}
+
+			back := *prev
+			back.Version = cur.Version
+			restored, err = tx.update(ctx, &back)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.audit(ctx, userID, debtID, model.AuditUndo, cur, restored, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to undo debt change: %w", err)
+	}
+
+	s.logger.Debugf("successfully undone change of debt (ID: %d, event: %d)", restored.ID, eventID)
+	return restored, nil
+}
+
+// recreate — inserts the deleted debt back, snapshots written before createdAt was kept get the current time
+func (s *DebtStorage) recreate(ctx context.Context, debt *model.Debt, createdAt *time.Time) (*model.Debt, error) {
+	q := `INSERT INTO debt (id, user_id, description, amount, return_date, version, created_at)
+		 VALUES (?1, ?2, ?3, ?4, ?5, ?6, COALESCE(?7, unixepoch()))
+		 RETURNING version`
+
+	res := *debt
+	res.Version++
+
+	err := s.db.QueryRowContext(ctx, q,
+		debt.ID,
+		debt.UserID,
+		debt.Description,
+		debt.Amount,
+		unix(debt.ReturnDate),
+		res.Version,
+		unix(createdAt),
+	).Scan(&res.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// debtSnapshot — JSON layout of the debt in audit_event, a flat model.Debt plus the creation time of a removed debt
+type debtSnapshot struct {
+	model.Debt
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// snapshot — JSON value of the debt, nil debt is stored as NULL
+func snapshot(d *model.Debt, createdAt *time.Time) (*string, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(debtSnapshot{Debt: *d, CreatedAt: createdAt})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal debt snapshot: %w", err)
+	}
+
+	res := string(data)
+	return &res, nil
+}
+
+func restore(data sql.NullString) (*model.Debt, error) {
+	d, _, err := restoreSnapshot(data)
+	return d, err
+}
+
+// restoreSnapshot — the debt and its creation time, which is nil for snapshots without it
+func restoreSnapshot(data sql.NullString) (*model.Debt, *time.Time, error) {
+	if !data.Valid {
+		return nil, nil, nil
+	}
+
+	var d debtSnapshot
+	if err := json.Unmarshal([]byte(data.String), &d); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal debt snapshot: %w", err)
+	}
+
+	return &d.Debt, d.CreatedAt, nil
+}
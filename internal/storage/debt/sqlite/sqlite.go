@@ -0,0 +1,367 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"drillCore/internal/model"
+	debtStorage "drillCore/internal/storage/debt"
+	"drillCore/internal/storage/sqlitedb"
+
+	"go.uber.org/zap"
+)
+
+// DebtStorage — debtStorage.Storage on SQLite, same semantics as the postgres one.
+// Times are stored as unix seconds.
+type DebtStorage struct {
+	conn   *sql.DB
+	db     sqlitedb.Querier // conn, or the transaction inside WithTx
+	logger *zap.SugaredLogger
+}
+
+func New(conn *sql.DB, logger *zap.SugaredLogger) *DebtStorage {
+	return &DebtStorage{conn: conn, db: conn, logger: logger}
+}
+
+func (s *DebtStorage) Ping(ctx context.Context) error {
+	if err := s.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// WithTx — unit of work: every call on tx runs in one transaction, which is committed
+// when fn returns nil. Nested calls join the outer transaction.
+func (s *DebtStorage) WithTx(ctx context.Context, fn func(tx *DebtStorage) error) error {
+	if _, ok := s.db.(*sql.Tx); ok {
+		return fn(s)
+	}
+
+	return sqlitedb.WithTx(ctx, s.conn, func(tx *sql.Tx) error {
+		return fn(&DebtStorage{conn: s.conn, db: tx, logger: s.logger})
+	})
+}
+
+func (s *DebtStorage) Save(ctx context.Context, debt *model.Debt) (int64, error) {
+	q := `INSERT INTO debt (user_id, description, amount, return_date)
+		 VALUES (?1, ?2, ?3, ?4)
+		 RETURNING id, version`
+
+	saved := *debt
+	err := s.WithTx(ctx, func(tx *DebtStorage) error {
+		err := tx.db.QueryRowContext(ctx, q,
+			debt.UserID,
+			debt.Description,
+			debt.Amount,
+			unix(debt.ReturnDate),
+		).Scan(&saved.ID, &saved.Version)
+		if err != nil {
+			return fmt.Errorf("failed to insert debt: %w", err)
+		}
+
+		_, err = tx.audit(ctx, debt.UserID, saved.ID, model.AuditCreate, nil, &saved, nil)
+		return err
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	debt.Version = saved.Version
+
+	s.logger.Debugf("successfully added debt (ID: %d) for user %d", saved.ID, debt.UserID)
+	return saved.ID, nil
+}
+
+func (s *DebtStorage) Debt(ctx context.Context, userID, id int64) (*model.Debt, error) {
+	q := `SELECT id, user_id, description, amount, return_date, version FROM debt WHERE id = ?1 AND user_id = ?2`
+
+	d, err := scanDebt(s.db.QueryRowContext(ctx, q, id, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debt: %w", err)
+	}
+
+	return d, nil
+}
+
+var (
+	// debtsOrder — whitelisted ORDER BY clauses, never build them from user input
+	debtsOrder = map[model.DebtSort]string{
+		// upcoming debts first, then overdue ones, debts without return date go last
+		model.SortByDueDate: `ORDER BY
+			CASE
+				WHEN return_date IS NULL THEN 2
+				WHEN return_date < unixepoch() THEN 1
+				ELSE 0
+			END,
+			return_date,
+			id`,
+		model.SortByAmount:  `ORDER BY amount DESC, id`,
+		model.SortByCreated: `ORDER BY created_at DESC, id DESC`,
+		model.SortByName:    `ORDER BY ` + sqlitedb.LowerFunc + `(description), id`,
+	}
+
+	// debtsFilter — whitelisted conditions appended to WHERE
+	debtsFilter = map[model.DebtFilter]string{
+		model.FilterAll:     ``,
+		model.FilterOverdue: ` AND return_date < unixepoch()`,
+		model.FilterDueThisMonth: ` AND return_date >= unixepoch('now', 'start of month')
+			AND return_date < unixepoch('now', 'start of month', '+1 month')`,
+		model.FilterNoDate: ` AND return_date IS NULL`,
+	}
+)
+
+func (s *DebtStorage) DebtsPage(ctx context.Context, userID int64, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error) {
+	return s.page(ctx, userID, "", cursor, limit, settings)
+}
+
+func (s *DebtStorage) SearchDebts(ctx context.Context, userID int64, query string, cursor, limit int) (*model.DebtPage, error) {
+	return s.page(ctx, userID, query, cursor, limit, nil)
+}
+
+func (s *DebtStorage) page(ctx context.Context, userID int64, query string, cursor, limit int, settings *model.ListSettings) (*model.DebtPage, error) {
+	if settings == nil {
+		settings = &model.ListSettings{}
+	}
+
+	order, ok := debtsOrder[settings.Sort]
+	if !ok {
+		return nil, fmt.Errorf("failed to get debts page: unknown sort %d", settings.Sort)
+	}
+
+	cond, ok := debtsFilter[settings.Filter]
+	if !ok {
+		return nil, fmt.Errorf("failed to get debts page: unknown filter %d", settings.Filter)
+	}
+
+	filter := `WHERE user_id = ?1 AND (?2 = '' OR instr(` + sqlitedb.LowerFunc + `(description), ` + sqlitedb.LowerFunc + `(?2)) > 0)` + cond
+
+	res := &model.DebtPage{
+		Debts:  make([]*model.Debt, 0, limit),
+		Cursor: cursor,
+		Limit:  limit,
+	}
+
+	q := `SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM debt ` + filter
+
+	err := s.db.QueryRowContext(ctx, q, userID, query).Scan(&res.Total, &res.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count debts: %w", err)
+	}
+
+	if res.Total == 0 {
+		return res, nil
+	}
+
+	q = `SELECT id, user_id, description, amount, return_date, version
+		 FROM debt ` + filter + `
+		 ` + order + `
+		 LIMIT ?3 OFFSET ?4`
+
+	rows, err := s.db.QueryContext(ctx, q, userID, query, limit, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debts page: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		debt, err := scanDebt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan debt: %w", err)
+		}
+
+		res.Debts = append(res.Debts, debt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read debts page: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *DebtStorage) ListSettings(ctx context.Context, userID int64) (*model.ListSettings, error) {
+	q := `SELECT sort, filter FROM debt_list_settings WHERE user_id = ?1`
+
+	var res model.ListSettings
+	err := s.db.QueryRowContext(ctx, q, userID).Scan(&res.Sort, &res.Filter)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.ListSettings{}, nil
+		}
+		return nil, fmt.Errorf("failed to get list settings: %w", err)
+	}
+
+	return &res, nil
+}
+
+func (s *DebtStorage) SaveListSettings(ctx context.Context, userID int64, settings *model.ListSettings) error {
+	q := `INSERT INTO debt_list_settings (user_id, sort, filter, updated_at)
+		 VALUES (?1, ?2, ?3, unixepoch())
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET sort = excluded.sort, filter = excluded.filter, updated_at = excluded.updated_at`
+
+	_, err := s.db.ExecContext(ctx, q, userID, settings.Sort, settings.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to save list settings: %w", err)
+	}
+
+	s.logger.Debugf("successfully saved list settings for user %d: %+v", userID, settings)
+	return nil
+}
+
+func (s *DebtStorage) Update(ctx context.Context, userID int64, debt *model.Debt) error {
+	if _, err := s.change(ctx, userID, debt, model.AuditUpdate); err != nil {
+		return fmt.Errorf("failed to update debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully updated debt (ID: %d, version: %d)", debt.ID, debt.Version)
+	return nil
+}
+
+func (s *DebtStorage) Delete(ctx context.Context, userID int64, debt *model.Debt) (int64, error) {
+	eventID, err := s.change(ctx, userID, debt, model.AuditDelete)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully deleted debt (ID: %d)", debt.ID)
+	return eventID, nil
+}
+
+func (s *DebtStorage) Pay(ctx context.Context, userID int64, debt *model.Debt) (int64, error) {
+	eventID, err := s.change(ctx, userID, debt, model.AuditPay)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pay debt: %w", err)
+	}
+
+	s.logger.Debugf("successfully paid debt (ID: %d), left: %d", debt.ID, debt.Amount)
+	return eventID, nil
+}
+
+// change — versioned update or delete of the debt together with its audit event, returns the event ID
+func (s *DebtStorage) change(ctx context.Context, userID int64, debt *model.Debt, action model.AuditAction) (int64, error) {
+	remove := action == model.AuditDelete || (action == model.AuditPay && debt.Amount == 0)
+
+	var (
+		after     *model.Debt
+		createdAt *time.Time
+		eventID   int64
+	)
+	err := s.WithTx(ctx, func(tx *DebtStorage) error {
+		before, err := tx.current(ctx, userID, debt)
+		if err != nil {
+			return err
+		}
+
+		if !remove {
+			after, err = tx.update(ctx, debt)
+			if err != nil {
+				return err
+			}
+		} else if createdAt, err = tx.delete(ctx, debt.ID); err != nil {
+			return err
+		}
+
+		eventID, err = tx.audit(ctx, userID, debt.ID, action, before, after, createdAt)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if after != nil {
+		debt.Version = after.Version
+	}
+	return eventID, nil
+}
+
+// current — the stored debt of the user, ErrDebtConflict means it was changed since debt was read.
+// There is a single connection, so nobody can change it till the end of the transaction.
+func (s *DebtStorage) current(ctx context.Context, userID int64, debt *model.Debt) (*model.Debt, error) {
+	q := `SELECT id, user_id, description, amount, return_date, version
+		 FROM debt WHERE id = ?1 AND user_id = ?2`
+
+	cur, err := scanDebt(s.db.QueryRowContext(ctx, q, debt.ID, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	if cur.Version != debt.Version {
+		return nil, debtStorage.ErrDebtConflict
+	}
+
+	return cur, nil
+}
+
+func (s *DebtStorage) update(ctx context.Context, debt *model.Debt) (*model.Debt, error) {
+	q := `UPDATE debt
+		 SET description = ?1,
+		     amount = ?2,
+		     return_date = ?3,
+		     version = version + 1,
+		     updated_at = unixepoch()
+		 WHERE id = ?4
+		 RETURNING version`
+
+	after := *debt
+	err := s.db.QueryRowContext(ctx, q,
+		debt.Description,
+		debt.Amount,
+		unix(debt.ReturnDate),
+		debt.ID,
+	).Scan(&after.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &after, nil
+}
+
+// delete — removes the debt and returns its creation time for the audit snapshot
+func (s *DebtStorage) delete(ctx context.Context, id int64) (*time.Time, error) {
+	var createdAt int64
+	if err := s.db.QueryRowContext(ctx, "DELETE FROM debt WHERE id = ?1 RETURNING created_at", id).Scan(&createdAt); err != nil {
+		return nil, err
+	}
+
+	t := time.Unix(createdAt, 0)
+	return &t, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDebt(row scanner) (*model.Debt, error) {
+	var d model.Debt
+	var description sql.NullString
+	var date sql.NullInt64
+
+	err := row.Scan(&d.ID, &d.UserID, &description, &d.Amount, &date, &d.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, debtStorage.ErrDebtNotFound
+		}
+		return nil, err
+	}
+
+	d.Description = description.String
+	if date.Valid {
+		t := time.Unix(date.Int64, 0)
+		d.ReturnDate = &t
+	}
+	return &d, nil
+}
+
+// unix — nullable time in the storage format
+func unix(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+
+	v := t.Unix()
+	return &v
+}
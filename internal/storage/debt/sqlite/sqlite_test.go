@@ -0,0 +1,49 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"drillCore/internal/config"
+	debtStorage "drillCore/internal/storage/debt"
+	"drillCore/internal/storage/debt/sqlite"
+	"drillCore/internal/storage/debt/storagetest"
+	"drillCore/internal/storage/migrate"
+	"drillCore/internal/storage/sqlitedb"
+	"drillCore/migrations"
+
+	"go.uber.org/zap"
+)
+
+// newStorage — storage on a migrated database in a temp file
+func newStorage(t *testing.T) debtStorage.Storage {
+	logger := zap.NewNop().Sugar()
+
+	cfg := &config.DbEnvs{
+		Driver:           config.DriverSQLite,
+		Path:             filepath.Join(t.TempDir(), "drillcore.db"),
+		StatementTimeout: 5 * time.Second,
+	}
+
+	db, err := sqlitedb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrate.NewSQLite(db, migrations.SQLite(), logger)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+
+	if err := m.Up(t.Context()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return sqlite.New(db, logger)
+}
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, newStorage)
+}
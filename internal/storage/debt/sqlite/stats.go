@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"drillCore/internal/model"
+)
+
+// knownUsers — everyone who ever touched the debt module, paid debts are gone but their audit stays
+const knownUsers = `SELECT user_id FROM debt
+	UNION SELECT user_id FROM audit_event
+	UNION SELECT user_id FROM debt_list_settings`
+
+func (s *DebtStorage) Stats(ctx context.Context) (*model.DebtStats, error) {
+	q := `SELECT
+			(SELECT COUNT(*) FROM (` + knownUsers + `) u),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE return_date < unixepoch()),
+			COALESCE(SUM(amount), 0)
+		 FROM debt`
+
+	var st model.DebtStats
+	if err := s.db.QueryRowContext(ctx, q).Scan(&st.Users, &st.Debts, &st.Overdue, &st.Amount); err != nil {
+		return nil, fmt.Errorf("failed to get debt stats: %w", err)
+	}
+
+	return &st, nil
+}
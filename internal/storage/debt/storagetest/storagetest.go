@@ -0,0 +1,788 @@
+package storagetest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"drillCore/internal/model"
+	debtStorage "drillCore/internal/storage/debt"
+)
+
+// NewStorage — empty storage for one test
+type NewStorage func(t *testing.T) debtStorage.Storage
+
+const (
+	owner    int64 = 1
+	stranger int64 = 2
+
+	window = time.Hour
+)
+
+// Run — the conformance suite every debt storage backend must pass
+func Run(t *testing.T, newStorage NewStorage) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, newStorage NewStorage)
+	}{
+		{"save", saveDebt},
+		{"pages", pages},
+		{"search", search},
+		{"sort", sortDebts},
+		{"filter", filterDebts},
+		{"list settings", listSettings},
+		{"invalid amount", invalidAmount},
+		{"version conflict", conflict},
+		{"ownership", ownership},
+		{"audit", audit},
+		{"undo", undo},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newStorage)
+		})
+	}
+}
+
+// saveDebt — the debt is stored as given, with the first version
+func saveDebt(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+	date := day(10)
+
+	cases := []struct {
+		name string
+		debt *model.Debt
+	}{
+		{"with return date", &model.Debt{UserID: owner, Description: "drill bit", Amount: 1500, ReturnDate: &date}},
+		{"without return date", &model.Debt{UserID: owner, Description: "", Amount: 1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := s.Save(t.Context(), tc.debt)
+			if err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			if id <= 0 {
+				t.Fatalf("got id %d, want a positive one", id)
+			}
+			if tc.debt.Version != 1 {
+				t.Fatalf("version of the saved debt is %d, want 1", tc.debt.Version)
+			}
+
+			got, err := s.Debt(t.Context(), owner, id)
+			if err != nil {
+				t.Fatalf("debt: %v", err)
+			}
+
+			want := *tc.debt
+			want.ID = id
+			assertDebt(t, got, &want)
+		})
+	}
+}
+
+// pages — cursor and limit slice the list, totals cover every debt of the user
+func pages(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+
+	var total int64
+	for i := range int64(5) {
+		save(t, s, &model.Debt{UserID: owner, Description: "drill", Amount: 100 * (i + 1)})
+		total += 100 * (i + 1)
+	}
+	save(t, s, &model.Debt{UserID: stranger, Description: "drill", Amount: 10000})
+
+	cases := []struct {
+		cursor, limit int
+		want          int
+	}{
+		{cursor: 0, limit: 2, want: 2},
+		{cursor: 2, limit: 2, want: 2},
+		{cursor: 4, limit: 2, want: 1},
+		{cursor: 6, limit: 2, want: 0},
+		{cursor: 0, limit: 10, want: 5},
+	}
+
+	seen := make(map[int64]struct{})
+	for _, tc := range cases {
+		page, err := s.DebtsPage(t.Context(), owner, tc.cursor, tc.limit, nil)
+		if err != nil {
+			t.Fatalf("page %d/%d: %v", tc.cursor, tc.limit, err)
+		}
+
+		if len(page.Debts) != tc.want || page.Total != 5 || page.TotalAmount != total {
+			t.Fatalf("page %d/%d: got %d debts of %d (amount %d), want %d of 5 (amount %d)",
+				tc.cursor, tc.limit, len(page.Debts), page.Total, page.TotalAmount, tc.want, total)
+		}
+
+		if tc.limit == 2 {
+			for _, d := range page.Debts {
+				if _, ok := seen[d.ID]; ok {
+					t.Fatalf("debt %d is on two pages", d.ID)
+				}
+				seen[d.ID] = struct{}{}
+			}
+		}
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("pages hold %d debts, want 5", len(seen))
+	}
+
+	empty, err := s.DebtsPage(t.Context(), 3, 0, 10, nil)
+	if err != nil {
+		t.Fatalf("page of a user without debts: %v", err)
+	}
+	if empty.Total != 0 || len(empty.Debts) != 0 {
+		t.Fatalf("user without debts got %d debts", empty.Total)
+	}
+}
+
+// search — case-insensitive substring of the description, unicode included
+func search(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+
+	bit := save(t, s, &model.Debt{UserID: owner, Description: "Drill bit", Amount: 100})
+	giga := save(t, s, &model.Debt{UserID: owner, Description: "GIGA DRILL", Amount: 200})
+	bur := save(t, s, &model.Debt{UserID: owner, Description: "Бур для Лаганна", Amount: 300})
+	save(t, s, &model.Debt{UserID: owner, Description: "spiral", Amount: 400})
+	save(t, s, &model.Debt{UserID: stranger, Description: "drill", Amount: 500})
+
+	cases := []struct {
+		query string
+		want  []int64
+	}{
+		{"drill", []int64{bit.ID, giga.ID}},
+		{"DRILL B", []int64{bit.ID}},
+		{"бур", []int64{bur.ID}},
+		{"ЛАГАНН", []int64{bur.ID}},
+		{"nothing", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			page, err := s.SearchDebts(t.Context(), owner, tc.query, 0, 10)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+
+			assertIDs(t, page, tc.want, false)
+		})
+	}
+}
+
+// sortDebts — every whitelisted order
+func sortDebts(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+
+	later, overdue, soon := day(40), day(-40), day(2)
+	a := save(t, s, &model.Debt{UserID: owner, Description: "b", Amount: 300, ReturnDate: &later})
+	b := save(t, s, &model.Debt{UserID: owner, Description: "A", Amount: 100, ReturnDate: &overdue})
+	c := save(t, s, &model.Debt{UserID: owner, Description: "c", Amount: 200})
+	d := save(t, s, &model.Debt{UserID: owner, Description: "d", Amount: 400, ReturnDate: &soon})
+
+	cases := []struct {
+		name string
+		sort model.DebtSort
+		want []int64
+	}{
+		// upcoming first, then overdue, then without date
+		{"due date", model.SortByDueDate, []int64{d.ID, a.ID, b.ID, c.ID}},
+		{"amount", model.SortByAmount, []int64{d.ID, a.ID, c.ID, b.ID}},
+		{"created", model.SortByCreated, []int64{d.ID, c.ID, b.ID, a.ID}},
+		{"name", model.SortByName, []int64{b.ID, a.ID, c.ID, d.ID}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := s.DebtsPage(t.Context(), owner, 0, 10, &model.ListSettings{Sort: tc.sort})
+			if err != nil {
+				t.Fatalf("page: %v", err)
+			}
+
+			assertIDs(t, page, tc.want, true)
+		})
+	}
+
+	if _, err := s.DebtsPage(t.Context(), owner, 0, 10, &model.ListSettings{Sort: -1}); err == nil {
+		t.Fatal("unknown sort is accepted")
+	}
+}
+
+// filterDebts — every whitelisted filter
+func filterDebts(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 12, 0, 0, 0, time.UTC)
+	overdueDate, laterDate := day(-40), day(40)
+
+	month := save(t, s, &model.Debt{UserID: owner, Description: "this month", Amount: 100, ReturnDate: &thisMonth})
+	overdue := save(t, s, &model.Debt{UserID: owner, Description: "overdue", Amount: 200, ReturnDate: &overdueDate})
+	later := save(t, s, &model.Debt{UserID: owner, Description: "later", Amount: 300, ReturnDate: &laterDate})
+	noDate := save(t, s, &model.Debt{UserID: owner, Description: "no date", Amount: 400})
+
+	wantOverdue := []int64{overdue.ID}
+	if thisMonth.Before(now) {
+		wantOverdue = append(wantOverdue, month.ID)
+	}
+
+	cases := []struct {
+		name   string
+		filter model.DebtFilter
+		want   []int64
+	}{
+		{"all", model.FilterAll, []int64{month.ID, overdue.ID, later.ID, noDate.ID}},
+		{"overdue", model.FilterOverdue, wantOverdue},
+		{"due this month", model.FilterDueThisMonth, []int64{month.ID}},
+		{"no date", model.FilterNoDate, []int64{noDate.ID}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := s.DebtsPage(t.Context(), owner, 0, 10, &model.ListSettings{Filter: tc.filter})
+			if err != nil {
+				t.Fatalf("page: %v", err)
+			}
+
+			assertIDs(t, page, tc.want, false)
+		})
+	}
+
+	if _, err := s.DebtsPage(t.Context(), owner, 0, 10, &model.ListSettings{Filter: -1}); err == nil {
+		t.Fatal("unknown filter is accepted")
+	}
+}
+
+// listSettings — defaults until saved, saved per user
+func listSettings(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+
+	got, err := s.ListSettings(t.Context(), owner)
+	if err != nil {
+		t.Fatalf("list settings: %v", err)
+	}
+	if *got != (model.ListSettings{}) {
+		t.Fatalf("got %+v before saving, want the defaults", got)
+	}
+
+	for _, want := range []model.ListSettings{
+		{Sort: model.SortByAmount, Filter: model.FilterOverdue},
+		{Sort: model.SortByName, Filter: model.FilterNoDate},
+	} {
+		if err := s.SaveListSettings(t.Context(), owner, &want); err != nil {
+			t.Fatalf("save list settings: %v", err)
+		}
+
+		got, err := s.ListSettings(t.Context(), owner)
+		if err != nil {
+			t.Fatalf("list settings: %v", err)
+		}
+		if *got != want {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	}
+
+	other, err := s.ListSettings(t.Context(), stranger)
+	if err != nil {
+		t.Fatalf("list settings of another user: %v", err)
+	}
+	if *other != (model.ListSettings{}) {
+		t.Fatalf("another user got %+v, want the defaults", other)
+	}
+}
+
+// conflict — a change made from a stale copy of the debt is rejected and changes nothing
+func conflict(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+	ctx := t.Context()
+
+	d := save(t, s, &model.Debt{UserID: owner, Description: "drill", Amount: 1000})
+	stale := *d
+
+	fresh := *d
+	fresh.Description = "giga drill"
+	if err := s.Update(ctx, owner, &fresh); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if fresh.Version != d.Version+1 {
+		t.Fatalf("version after update is %d, want %d", fresh.Version, d.Version+1)
+	}
+
+	want, err := s.Debt(ctx, owner, d.ID)
+	if err != nil {
+		t.Fatalf("debt: %v", err)
+	}
+	assertDebt(t, want, &fresh)
+
+	cases := []struct {
+		name string
+		call func(d *model.Debt) error
+	}{
+		{"update", func(d *model.Debt) error {
+			d.Amount = 1
+			return s.Update(ctx, owner, d)
+		}},
+		{"delete", func(d *model.Debt) error {
+			_, err := s.Delete(ctx, owner, d)
+			return err
+		}},
+		{"pay", func(d *model.Debt) error {
+			d.Amount = 500
+			_, err := s.Pay(ctx, owner, d)
+			return err
+		}},
+		{"pay in full", func(d *model.Debt) error {
+			d.Amount = 0
+			_, err := s.Pay(ctx, owner, d)
+			return err
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := stale
+			if err := tc.call(&c); !errors.Is(err, debtStorage.ErrDebtConflict) {
+				t.Fatalf("got error %v, want %v", err, debtStorage.ErrDebtConflict)
+			}
+
+			got, err := s.Debt(ctx, owner, d.ID)
+			if err != nil {
+				t.Fatalf("debt: %v", err)
+			}
+			assertDebt(t, got, want)
+		})
+	}
+}
+
+// invalidAmount — non-positive amounts are rejected and leave the debts as they were
+func invalidAmount(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+	ctx := t.Context()
+
+	d := save(t, s, &model.Debt{UserID: owner, Description: "drill", Amount: 1000})
+
+	cases := []struct {
+		name string
+		call func(d *model.Debt) error
+	}{
+		{"save zero", func(d *model.Debt) error {
+			_, err := s.Save(ctx, &model.Debt{UserID: owner, Description: "core", Amount: 0})
+			return err
+		}},
+		{"save negative", func(d *model.Debt) error {
+			_, err := s.Save(ctx, &model.Debt{UserID: owner, Description: "core", Amount: -1})
+			return err
+		}},
+		{"update zero", func(d *model.Debt) error {
+			d.Amount = 0
+			return s.Update(ctx, owner, d)
+		}},
+		{"pay negative", func(d *model.Debt) error {
+			d.Amount = -500
+			_, err := s.Pay(ctx, owner, d)
+			return err
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := *d
+			if err := tc.call(&c); err == nil {
+				t.Fatal("got no error for a non-positive amount")
+			}
+
+			got, err := s.Debt(ctx, owner, d.ID)
+			if err != nil {
+				t.Fatalf("debt: %v", err)
+			}
+			assertDebt(t, got, d)
+
+			page, err := s.DebtsPage(ctx, owner, 0, 10, nil)
+			if err != nil {
+				t.Fatalf("debts page: %v", err)
+			}
+			assertIDs(t, page, []int64{d.ID}, true)
+		})
+	}
+}
+
+// audit — every change leaves an event with the debt before and after it, newest first
+func audit(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+	ctx := t.Context()
+
+	d := save(t, s, &model.Debt{UserID: owner, Description: "drill", Amount: 1000})
+
+	d.Description = "giga drill"
+	if err := s.Update(ctx, owner, d); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	payID := pay(t, s, d, 600)
+
+	deleteID, err := s.Delete(ctx, owner, d)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	save(t, s, &model.Debt{UserID: stranger, Description: "foreign", Amount: 1})
+
+	trail := auditTrail(t, s, owner)
+
+	want := []struct {
+		action        model.AuditAction
+		before, after int64 // amounts, 0 means no snapshot
+	}{
+		{model.AuditDelete, 600, 0},
+		{model.AuditPay, 1000, 600},
+		{model.AuditUpdate, 1000, 1000},
+		{model.AuditCreate, 0, 1000},
+	}
+
+	if len(trail) != len(want) {
+		t.Fatalf("got %d audit events, want %d", len(trail), len(want))
+	}
+
+	for i, w := range want {
+		e := trail[i]
+		if e.Action != w.action || e.UserID != owner || e.DebtID != d.ID {
+			t.Fatalf("event %d is %s of debt %d by user %d, want %s of debt %d by user %d",
+				i, e.Action, e.DebtID, e.UserID, w.action, d.ID, owner)
+		}
+
+		if amount(e.Before) != w.before || amount(e.After) != w.after {
+			t.Fatalf("%s event: amounts %d -> %d, want %d -> %d",
+				e.Action, amount(e.Before), amount(e.After), w.before, w.after)
+		}
+	}
+
+	if trail[0].ID != deleteID || trail[1].ID != payID {
+		t.Fatalf("delete and pay returned events %d and %d, trail has %d and %d",
+			deleteID, payID, trail[0].ID, trail[1].ID)
+	}
+
+	if trail[2].Before.Description != "drill" || trail[2].After.Description != "giga drill" {
+		t.Fatalf("update event: %q -> %q", trail[2].Before.Description, trail[2].After.Description)
+	}
+
+	limited, err := s.AuditTrail(ctx, owner, 2)
+	if err != nil {
+		t.Fatalf("audit trail: %v", err)
+	}
+	if len(limited) != 2 || limited[0].ID != deleteID {
+		t.Fatalf("limited trail has %d events, want the 2 newest", len(limited))
+	}
+}
+
+// ownership — another user can neither read nor change a debt, whatever its ID
+func ownership(t *testing.T, newStorage NewStorage) {
+	s := newStorage(t)
+	ctx := t.Context()
+
+	d := save(t, s, &model.Debt{UserID: owner, Description: "drill parts", Amount: 1000})
+
+	// a payment of the owner, so there is something to undo
+	paid := *d
+	paid.Amount = 600
+	payID, err := s.Pay(ctx, owner, &paid)
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+
+	want, err := s.Debt(ctx, owner, d.ID)
+	if err != nil {
+		t.Fatalf("debt of the owner: %v", err)
+	}
+
+	trail := auditTrail(t, s, owner)
+
+	foreign := func() *model.Debt {
+		c := *want
+		return &c
+	}
+
+	cases := []struct {
+		name string
+		call func() error
+	}{
+		{"debt", func() error {
+			_, err := s.Debt(ctx, stranger, d.ID)
+			return err
+		}},
+		{"update", func() error {
+			c := foreign()
+			c.Amount, c.Description = 1, "mine now"
+			return s.Update(ctx, stranger, c)
+		}},
+		{"delete", func() error {
+			_, err := s.Delete(ctx, stranger, foreign())
+			return err
+		}},
+		{"pay", func() error {
+			c := foreign()
+			c.Amount = 100
+			_, err := s.Pay(ctx, stranger, c)
+			return err
+		}},
+		{"pay in full", func() error {
+			c := foreign()
+			c.Amount = 0
+			_, err := s.Pay(ctx, stranger, c)
+			return err
+		}},
+		{"undo", func() error {
+			_, err := s.Undo(ctx, stranger, payID, window)
+			return err
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); !errors.Is(err, debtStorage.ErrDebtNotFound) {
+				t.Fatalf("got error %v, want %v", err, debtStorage.ErrDebtNotFound)
+			}
+
+			got, err := s.Debt(ctx, owner, d.ID)
+			if err != nil {
+				t.Fatalf("debt of the owner: %v", err)
+			}
+			assertDebt(t, got, want)
+
+			if got := auditTrail(t, s, owner); len(got) != len(trail) {
+				t.Fatalf("owner has %d audit events, want %d", len(got), len(trail))
+			}
+
+			if got := auditTrail(t, s, stranger); len(got) != 0 {
+				t.Fatalf("stranger has %d audit events, want none", len(got))
+			}
+		})
+	}
+}
+
+// undo — the undo button reverts exactly the change it was attached to, and only while
+// that change is the latest one of the debt and within the window
+func undo(t *testing.T, newStorage NewStorage) {
+	t.Run("payment", func(t *testing.T) {
+		s := newStorage(t)
+		d := save(t, s, &model.Debt{UserID: owner, Description: "core drill", Amount: 1000})
+
+		eventID := pay(t, s, d, 700)
+
+		got, err := s.Undo(t.Context(), owner, eventID, window)
+		if err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+		if got.Amount != 1000 {
+			t.Fatalf("amount after undo is %d, want 1000", got.Amount)
+		}
+
+		// the same button twice: the undo itself is the latest change now
+		if _, err := s.Undo(t.Context(), owner, eventID, window); !errors.Is(err, debtStorage.ErrNothingToUndo) {
+			t.Fatalf("second undo: got error %v, want %v", err, debtStorage.ErrNothingToUndo)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		s := newStorage(t)
+		d := save(t, s, &model.Debt{UserID: owner, Description: "core drill", Amount: 1000})
+		newer := save(t, s, &model.Debt{UserID: owner, Description: "giga drill", Amount: 500})
+
+		eventID, err := s.Delete(t.Context(), owner, d)
+		if err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+
+		got, err := s.Undo(t.Context(), owner, eventID, window)
+		if err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+		if got.ID != d.ID || got.Amount != d.Amount || got.Description != d.Description {
+			t.Fatalf("restored debt %+v, want %+v", got, d)
+		}
+
+		if _, err := s.Debt(t.Context(), owner, d.ID); err != nil {
+			t.Fatalf("restored debt: %v", err)
+		}
+
+		// the restored debt keeps its creation time instead of jumping to the top
+		page, err := s.DebtsPage(t.Context(), owner, 0, 10, &model.ListSettings{Sort: model.SortByCreated})
+		if err != nil {
+			t.Fatalf("page: %v", err)
+		}
+		assertIDs(t, page, []int64{newer.ID, d.ID}, true)
+	})
+
+	t.Run("not the latest change", func(t *testing.T) {
+		s := newStorage(t)
+		d := save(t, s, &model.Debt{UserID: owner, Description: "core drill", Amount: 1000})
+
+		first := pay(t, s, d, 700)
+		pay(t, s, d, 400)
+
+		if _, err := s.Undo(t.Context(), owner, first, window); !errors.Is(err, debtStorage.ErrNothingToUndo) {
+			t.Fatalf("got error %v, want %v", err, debtStorage.ErrNothingToUndo)
+		}
+
+		assertAmount(t, s, d.ID, 400)
+	})
+
+	t.Run("after an edit", func(t *testing.T) {
+		s := newStorage(t)
+		d := save(t, s, &model.Debt{UserID: owner, Description: "core drill", Amount: 1000})
+
+		eventID := pay(t, s, d, 700)
+
+		d.Description = "giga drill"
+		if err := s.Update(t.Context(), owner, d); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+
+		if _, err := s.Undo(t.Context(), owner, eventID, window); !errors.Is(err, debtStorage.ErrNothingToUndo) {
+			t.Fatalf("got error %v, want %v", err, debtStorage.ErrNothingToUndo)
+		}
+
+		assertAmount(t, s, d.ID, 700)
+	})
+
+	t.Run("not undoable", func(t *testing.T) {
+		s := newStorage(t)
+		d := save(t, s, &model.Debt{UserID: owner, Description: "core drill", Amount: 1000})
+
+		trail := auditTrail(t, s, owner)
+		if _, err := s.Undo(t.Context(), owner, trail[0].ID, window); !errors.Is(err, debtStorage.ErrNothingToUndo) {
+			t.Fatalf("undo of create: got error %v, want %v", err, debtStorage.ErrNothingToUndo)
+		}
+
+		assertAmount(t, s, d.ID, 1000)
+	})
+
+	t.Run("window passed", func(t *testing.T) {
+		s := newStorage(t)
+		d := save(t, s, &model.Debt{UserID: owner, Description: "core drill", Amount: 1000})
+
+		eventID := pay(t, s, d, 700)
+
+		// a negative window has passed for any change, however fresh
+		if _, err := s.Undo(t.Context(), owner, eventID, -time.Second); !errors.Is(err, debtStorage.ErrNothingToUndo) {
+			t.Fatalf("got error %v, want %v", err, debtStorage.ErrNothingToUndo)
+		}
+
+		assertAmount(t, s, d.ID, 700)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		s := newStorage(t)
+
+		if _, err := s.Undo(t.Context(), owner, 42, window); !errors.Is(err, debtStorage.ErrDebtNotFound) {
+			t.Fatalf("got error %v, want %v", err, debtStorage.ErrDebtNotFound)
+		}
+	})
+}
+
+// pay — pays the debt down to amount, d gets the new version, returns the audit event
+func pay(t *testing.T, s debtStorage.Storage, d *model.Debt, amount int64) int64 {
+	t.Helper()
+
+	d.Amount = amount
+	eventID, err := s.Pay(t.Context(), d.UserID, d)
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+
+	return eventID
+}
+
+func assertAmount(t *testing.T, s debtStorage.Storage, id, want int64) {
+	t.Helper()
+
+	got, err := s.Debt(t.Context(), owner, id)
+	if err != nil {
+		t.Fatalf("debt %d: %v", id, err)
+	}
+
+	if got.Amount != want {
+		t.Fatalf("amount is %d, want %d", got.Amount, want)
+	}
+}
+
+func amount(d *model.Debt) int64 {
+	if d == nil {
+		return 0
+	}
+	return d.Amount
+}
+
+// assertIDs — the page holds exactly the debts, in order when ordered is set
+func assertIDs(t *testing.T, page *model.DebtPage, want []int64, ordered bool) {
+	t.Helper()
+
+	got := make([]int64, 0, len(page.Debts))
+	for _, d := range page.Debts {
+		got = append(got, d.ID)
+	}
+
+	if !ordered {
+		slices.Sort(got)
+		want = slices.Sorted(slices.Values(want))
+	}
+
+	if !slices.Equal(got, want) || page.Total != len(want) {
+		t.Fatalf("got debts %v (total %d), want %v", got, page.Total, want)
+	}
+}
+
+// day — midnight of the day n days from today, in UTC and to the second as every backend stores it
+func day(n int) time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+n, 0, 0, 0, 0, time.UTC)
+}
+
+// save — saves the debt and returns it as stored
+func save(t *testing.T, s debtStorage.Storage, d *model.Debt) *model.Debt {
+	t.Helper()
+
+	id, err := s.Save(t.Context(), d)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	res, err := s.Debt(t.Context(), d.UserID, id)
+	if err != nil {
+		t.Fatalf("debt %d: %v", id, err)
+	}
+
+	return res
+}
+
+func auditTrail(t *testing.T, s debtStorage.Storage, userID int64) []*model.AuditEvent {
+	t.Helper()
+
+	res, err := s.AuditTrail(t.Context(), userID, 100)
+	if err != nil {
+		t.Fatalf("audit trail of user %d: %v", userID, err)
+	}
+
+	return res
+}
+
+func assertDebt(t *testing.T, got, want *model.Debt) {
+	t.Helper()
+
+	if got.ID != want.ID || got.UserID != want.UserID || got.Description != want.Description ||
+		got.Amount != want.Amount || got.Version != want.Version || !sameDate(got.ReturnDate, want.ReturnDate) {
+		t.Fatalf("got debt %+v, want %+v", got, want)
+	}
+}
+
+// sameDate — return dates are compared to the second, sqlite keeps unix seconds
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Unix() == b.Unix()
+}
@@ -0,0 +1,337 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// versionTable — same table goose uses, so databases migrated by the goose container are picked up as is
+const versionTable = "goose_db_version"
+
+// lockKey — pg_advisory_lock key, only one instance migrates at a time
+const lockKey int64 = 0x6472696c6c436f72 // "drillCor"
+
+const (
+	upAnnotation   = "-- +goose Up"
+	downAnnotation = "-- +goose Down"
+)
+
+var (
+	ErrSchemaMismatch   = errors.New("database schema version mismatch")
+	ErrNoMigrations     = errors.New("no migrations found")
+	ErrInvalidMigration = errors.New("invalid migration")
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration *Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []*Migration
+	logger     *zap.SugaredLogger
+}
+
+func New(pool *pgxpool.Pool, fsys fs.FS, logger *zap.SugaredLogger) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{pool: pool, migrations: migrations, logger: logger}, nil
+}
+
+// Load — reads NNNNN_name.sql files with goose Up/Down annotations, sorted by version
+func Load(fsys fs.FS) ([]*Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	res := make([]*Migration, 0, len(files))
+	seen := make(map[int64]string, len(files))
+	for _, f := range files {
+		m, err := parse(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+
+		if prev, ok := seen[m.Version]; ok {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, m.Version, prev, f)
+		}
+		seen[m.Version] = f
+
+		res = append(res, m)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version < res[j].Version
+	})
+
+	return res, nil
+}
+
+func parse(fsys fs.FS, file string) (*Migration, error) {
+	name := strings.TrimSuffix(path.Base(file), ".sql")
+
+	versionStr, _, ok := strings.Cut(name, "_")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: expected NNNNN_name.sql", ErrInvalidMigration, file)
+	}
+
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil || version <= 0 {
+		return nil, fmt.Errorf("%w: %s: bad version %q", ErrInvalidMigration, file, versionStr)
+	}
+
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+	}
+
+	content := string(data)
+
+	upIdx := strings.Index(content, upAnnotation)
+	if upIdx < 0 {
+		return nil, fmt.Errorf("%w: %s: missing %q", ErrInvalidMigration, file, upAnnotation)
+	}
+
+	up := content[upIdx+len(upAnnotation):]
+	down := ""
+	if downIdx := strings.Index(up, downAnnotation); downIdx >= 0 {
+		down = up[downIdx+len(downAnnotation):]
+		up = up[:downIdx]
+	}
+
+	return &Migration{
+		Version: version,
+		Name:    name,
+		Up:      strings.TrimSpace(up),
+		Down:    strings.TrimSpace(down),
+	}, nil
+}
+
+// Latest — version of the newest embedded migration
+func (m *Migrator) Latest() int64 {
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up — applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mg.Up); err != nil {
+					return err
+				}
+
+				_, err := tx.Exec(ctx, `INSERT INTO `+versionTable+` (version_id, is_applied) VALUES ($1, TRUE)`, mg.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", mg.Name, err)
+			}
+
+			m.logger.Infof("applied migration %s", mg.Name)
+		}
+
+		return nil
+	})
+}
+
+// Down — rolls back the last applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if mg.Down != "" {
+					if _, err := tx.Exec(ctx, mg.Down); err != nil {
+						return err
+					}
+				}
+
+				_, err := tx.Exec(ctx, `DELETE FROM `+versionTable+` WHERE version_id = $1`, mg.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %s: %w", mg.Name, err)
+			}
+
+			m.logger.Infof("rolled back migration %s", mg.Name)
+
+			return nil
+		}
+
+		m.logger.Info("no migrations to roll back")
+
+		return nil
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	var res []*Status
+
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		res = make([]*Status, 0, len(m.migrations))
+		for _, mg := range m.migrations {
+			st := &Status{Migration: mg}
+			if at, ok := applied[mg.Version]; ok {
+				st.Applied = true
+				st.AppliedAt = &at
+			}
+
+			res = append(res, st)
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// Version — highest applied migration version, 0 for an empty database
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	var version int64
+
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for v := range applied {
+			version = max(version, v)
+		}
+
+		return nil
+	})
+
+	return version, err
+}
+
+// Check — refuses to work with a database that is behind or ahead of the embedded migrations
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version != m.Latest() {
+		return fmt.Errorf("%w: database is at %d, app expects %d", ErrSchemaMismatch, version, m.Latest())
+	}
+
+	return nil
+}
+
+// locked — runs fn on a single connection holding the migrations advisory lock
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("failed to take migrations lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			m.logger.Errorf("failed to release migrations lock: %v", err)
+		}
+	}()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func ensureVersionTable(ctx context.Context, conn *pgxpool.Conn) error {
+	q := `CREATE TABLE IF NOT EXISTS ` + versionTable + ` (
+		id SERIAL PRIMARY KEY,
+		version_id BIGINT NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp TIMESTAMP DEFAULT NOW()
+	)`
+
+	if _, err := conn.Exec(ctx, q); err != nil {
+		return fmt.Errorf("failed to create version table: %w", err)
+	}
+
+	return nil
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	q := `SELECT version_id, COALESCE(MAX(tstamp), NOW()) FROM ` + versionTable + `
+		 WHERE is_applied AND version_id > 0
+		 GROUP BY version_id`
+
+	rows, err := conn.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+
+		res[version] = at
+	}
+
+	return res, rows.Err()
+}
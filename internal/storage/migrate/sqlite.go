@@ -0,0 +1,185 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SQLiteMigrator — Migrator for the SQLite backend, the database has a single connection
+// so there is no lock to take
+type SQLiteMigrator struct {
+	db         *sql.DB
+	migrations []*Migration
+	logger     *zap.SugaredLogger
+}
+
+func NewSQLite(db *sql.DB, fsys fs.FS, logger *zap.SugaredLogger) (*SQLiteMigrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteMigrator{db: db, migrations: migrations, logger: logger}, nil
+}
+
+func (m *SQLiteMigrator) Latest() int64 {
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *SQLiteMigrator) Up(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, mg := range m.migrations {
+		if _, ok := applied[mg.Version]; ok {
+			continue
+		}
+
+		err := m.tx(ctx, mg.Up, `INSERT INTO `+versionTable+` (version_id, is_applied) VALUES (?, TRUE)`, mg.Version)
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", mg.Name, err)
+		}
+
+		m.logger.Infof("applied migration %s", mg.Name)
+	}
+
+	return nil
+}
+
+func (m *SQLiteMigrator) Down(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mg := m.migrations[i]
+		if _, ok := applied[mg.Version]; !ok {
+			continue
+		}
+
+		err := m.tx(ctx, mg.Down, `DELETE FROM `+versionTable+` WHERE version_id = ?`, mg.Version)
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration %s: %w", mg.Name, err)
+		}
+
+		m.logger.Infof("rolled back migration %s", mg.Name)
+
+		return nil
+	}
+
+	m.logger.Info("no migrations to roll back")
+
+	return nil
+}
+
+func (m *SQLiteMigrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		st := &Status{Migration: mg}
+		if at, ok := applied[mg.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+
+		res = append(res, st)
+	}
+
+	return res, nil
+}
+
+func (m *SQLiteMigrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	for v := range applied {
+		version = max(version, v)
+	}
+
+	return version, nil
+}
+
+func (m *SQLiteMigrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version != m.Latest() {
+		return fmt.Errorf("%w: database is at %d, app expects %d", ErrSchemaMismatch, version, m.Latest())
+	}
+
+	return nil
+}
+
+// tx — runs the migration script and the version table change in one transaction
+func (m *SQLiteMigrator) tx(ctx context.Context, script, versionQuery string, version int64) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if script != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, versionQuery, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *SQLiteMigrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	q := `CREATE TABLE IF NOT EXISTS ` + versionTable + ` (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp INTEGER NOT NULL DEFAULT (unixepoch())
+	)`
+
+	if _, err := m.db.ExecContext(ctx, q); err != nil {
+		return nil, fmt.Errorf("failed to create version table: %w", err)
+	}
+
+	q = `SELECT version_id, MAX(tstamp) FROM ` + versionTable + `
+		 WHERE is_applied AND version_id > 0
+		 GROUP BY version_id`
+
+	rows, err := m.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[int64]time.Time)
+	for rows.Next() {
+		var version, at int64
+
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+
+		res[version] = time.Unix(at, 0)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,39 @@
+package pg
+
+import (
+	"context"
+	"strings"
+
+	"drillCore/internal/tracing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTracer — pgx.QueryTracer starting a span per query
+type queryTracer struct{}
+
+type spanKey struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, span := tracing.Start(ctx, "pg.query",
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", strings.Join(strings.Fields(data.SQL), " ")),
+	)
+
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	if data.Err == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+
+	tracing.End(span, data.Err)
+}
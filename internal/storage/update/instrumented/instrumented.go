@@ -0,0 +1,94 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+	updateStorage "drillCore/internal/storage/update"
+	"drillCore/internal/tracing"
+)
+
+// UpdateStorage — records the latency and a span of every call of the wrapped storage
+type UpdateStorage struct {
+	next updateStorage.Storage
+}
+
+func New(next updateStorage.Storage) *UpdateStorage {
+	return &UpdateStorage{next: next}
+}
+
+// begin — starts the span of operation, done records its latency and ends the span
+func begin(ctx context.Context, operation string) (context.Context, func(err error)) {
+	ctx, span := tracing.Start(ctx, "storage."+operation)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		metrics.StorageDuration.WithLabelValues(operation, metrics.Result(err)).Observe(metrics.Since(start))
+		tracing.End(span, err)
+	}
+}
+
+func (s *UpdateStorage) Offset(ctx context.Context) (offset int, err error) {
+	ctx, done := begin(ctx, "offset")
+	defer func() { done(err) }()
+
+	return s.next.Offset(ctx)
+}
+
+func (s *UpdateStorage) IsProcessed(ctx context.Context, updateID int) (ok bool, err error) {
+	ctx, done := begin(ctx, "is_processed")
+	defer func() { done(err) }()
+
+	return s.next.IsProcessed(ctx, updateID)
+}
+
+func (s *UpdateStorage) Commit(ctx context.Context, updateID int) (err error) {
+	ctx, done := begin(ctx, "commit")
+	defer func() { done(err) }()
+
+	return s.next.Commit(ctx, updateID)
+}
+
+func (s *UpdateStorage) CommitDeadLetter(ctx context.Context, dl *model.DeadLetter) (id int64, err error) {
+	ctx, done := begin(ctx, "commit_dead_letter")
+	defer func() { done(err) }()
+
+	return s.next.CommitDeadLetter(ctx, dl)
+}
+
+func (s *UpdateStorage) ReplayQueue(ctx context.Context, limit int) (res []*model.DeadLetter, err error) {
+	ctx, done := begin(ctx, "replay_queue")
+	defer func() { done(err) }()
+
+	return s.next.ReplayQueue(ctx, limit)
+}
+
+func (s *UpdateStorage) DeadLetters(ctx context.Context, limit int) (res []*model.DeadLetter, err error) {
+	ctx, done := begin(ctx, "dead_letters")
+	defer func() { done(err) }()
+
+	return s.next.DeadLetters(ctx, limit)
+}
+
+func (s *UpdateStorage) RequeueDeadLetter(ctx context.Context, id int64) (err error) {
+	ctx, done := begin(ctx, "requeue_dead_letter")
+	defer func() { done(err) }()
+
+	return s.next.RequeueDeadLetter(ctx, id)
+}
+
+func (s *UpdateStorage) FailDeadLetter(ctx context.Context, id int64, errText string, attempts int) (err error) {
+	ctx, done := begin(ctx, "fail_dead_letter")
+	defer func() { done(err) }()
+
+	return s.next.FailDeadLetter(ctx, id, errText, attempts)
+}
+
+func (s *UpdateStorage) DeleteDeadLetter(ctx context.Context, id int64) (err error) {
+	ctx, done := begin(ctx, "delete_dead_letter")
+	defer func() { done(err) }()
+
+	return s.next.DeleteDeadLetter(ctx, id)
+}
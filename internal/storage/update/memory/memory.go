@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"drillCore/internal/model"
+	updateStorage "drillCore/internal/storage/update"
+)
+
+// UpdateStorage — in-memory updateStorage.Storage, for demo mode.
+// Nothing survives a restart.
+type UpdateStorage struct {
+	mu sync.Mutex
+
+	offset    int
+	processed map[int]time.Time
+
+	nextDeadLetterID int64
+	deadLetters      []*model.DeadLetter // oldest first
+
+	now func() time.Time
+}
+
+func New() *UpdateStorage {
+	return &UpdateStorage{processed: make(map[int]time.Time), now: time.Now}
+}
+
+func (s *UpdateStorage) Offset(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.offset, nil
+}
+
+// IsProcessed — only the committed updates, the debt audit lives in another storage,
+// and a crash between the handler and Commit loses it anyway
+func (s *UpdateStorage) IsProcessed(_ context.Context, updateID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.processed[updateID]
+
+	return ok, nil
+}
+
+func (s *UpdateStorage) Commit(_ context.Context, updateID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commit(updateID)
+
+	return nil
+}
+
+func (s *UpdateStorage) commit(updateID int) {
+	now := s.now()
+	s.processed[updateID] = now
+	s.offset = max(s.offset, updateID+1)
+
+	for id, at := range s.processed {
+		if now.Sub(at) > updateStorage.Retention {
+			delete(s.processed, id)
+		}
+	}
+}
+
+func (s *UpdateStorage) CommitDeadLetter(_ context.Context, dl *model.DeadLetter) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextDeadLetterID++
+
+	saved := *dl
+	saved.ID = s.nextDeadLetterID
+	saved.Update = slices.Clone(updateStorage.RawUpdate(dl.Update))
+	saved.Status = model.DeadLetterFailed
+	saved.CreatedAt = s.now()
+	saved.UpdatedAt = saved.CreatedAt
+
+	s.deadLetters = append(s.deadLetters, &saved)
+	s.commit(int(dl.UpdateID))
+
+	return saved.ID, nil
+}
+
+func (s *UpdateStorage) ReplayQueue(_ context.Context, limit int) ([]*model.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*model.DeadLetter
+	for _, dl := range s.deadLetters {
+		if len(res) == limit {
+			break
+		}
+
+		if dl.Status == model.DeadLetterReplay {
+			res = append(res, copyDeadLetter(dl))
+		}
+	}
+
+	return res, nil
+}
+
+func (s *UpdateStorage) DeadLetters(_ context.Context, limit int) ([]*model.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*model.DeadLetter
+	for i := len(s.deadLetters) - 1; i >= 0 && len(res) < limit; i-- {
+		res = append(res, copyDeadLetter(s.deadLetters[i]))
+	}
+
+	return res, nil
+}
+
+func (s *UpdateStorage) RequeueDeadLetter(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dl, err := s.deadLetter(id)
+	if err != nil {
+		return err
+	}
+
+	dl.Status = model.DeadLetterReplay
+	dl.UpdatedAt = s.now()
+
+	return nil
+}
+
+func (s *UpdateStorage) FailDeadLetter(_ context.Context, id int64, errText string, attempts int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dl, err := s.deadLetter(id)
+	if err != nil {
+		return err
+	}
+
+	dl.Status = model.DeadLetterFailed
+	dl.Error = errText
+	dl.Attempts += attempts
+	dl.UpdatedAt = s.now()
+
+	return nil
+}
+
+func (s *UpdateStorage) DeleteDeadLetter(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.deadLetters, func(dl *model.DeadLetter) bool { return dl.ID == id })
+	if i < 0 {
+		return fmt.Errorf("%w: %d", updateStorage.ErrDeadLetterNotFound, id)
+	}
+
+	s.deadLetters = slices.Delete(s.deadLetters, i, i+1)
+
+	return nil
+}
+
+func (s *UpdateStorage) deadLetter(id int64) (*model.DeadLetter, error) {
+	for _, dl := range s.deadLetters {
+		if dl.ID == id {
+			return dl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d", updateStorage.ErrDeadLetterNotFound, id)
+}
+
+func copyDeadLetter(dl *model.DeadLetter) *model.DeadLetter {
+	res := *dl
+	res.Update = slices.Clone(dl.Update)
+
+	return &res
+}
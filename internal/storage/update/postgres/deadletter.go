@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"drillCore/internal/model"
+	"drillCore/internal/storage/pg"
+	updateStorage "drillCore/internal/storage/update"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const deadLetterColumns = `id, update_id, user_id, chat_id, raw_update, error, attempts, status, created_at, updated_at`
+
+func (s *UpdateStorage) CommitDeadLetter(ctx context.Context, dl *model.DeadLetter) (int64, error) {
+	var id int64
+
+	err := pg.WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		q := `INSERT INTO dead_letter (update_id, user_id, chat_id, raw_update, error, attempts, status)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)
+			 RETURNING id`
+
+		err := tx.QueryRow(ctx, q, dl.UpdateID, dl.UserID, dl.ChatID, updateStorage.RawUpdate(dl.Update), dl.Error, dl.Attempts, string(model.DeadLetterFailed)).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to save dead letter: %w", err)
+		}
+
+		return s.commit(ctx, tx, int(dl.UpdateID))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *UpdateStorage) ReplayQueue(ctx context.Context, limit int) ([]*model.DeadLetter, error) {
+	q := `SELECT ` + deadLetterColumns + ` FROM dead_letter WHERE status = $1 ORDER BY id LIMIT $2`
+
+	return s.deadLetters(ctx, q, string(model.DeadLetterReplay), limit)
+}
+
+func (s *UpdateStorage) DeadLetters(ctx context.Context, limit int) ([]*model.DeadLetter, error) {
+	q := `SELECT ` + deadLetterColumns + ` FROM dead_letter ORDER BY id DESC LIMIT $1`
+
+	return s.deadLetters(ctx, q, limit)
+}
+
+func (s *UpdateStorage) RequeueDeadLetter(ctx context.Context, id int64) error {
+	q := `UPDATE dead_letter SET status = $2, updated_at = NOW() WHERE id = $1`
+
+	return s.execDeadLetter(ctx, id, q, id, string(model.DeadLetterReplay))
+}
+
+func (s *UpdateStorage) FailDeadLetter(ctx context.Context, id int64, errText string, attempts int) error {
+	q := `UPDATE dead_letter SET status = $2, error = $3, attempts = attempts + $4, updated_at = NOW() WHERE id = $1`
+
+	return s.execDeadLetter(ctx, id, q, id, string(model.DeadLetterFailed), errText, attempts)
+}
+
+func (s *UpdateStorage) DeleteDeadLetter(ctx context.Context, id int64) error {
+	return s.execDeadLetter(ctx, id, `DELETE FROM dead_letter WHERE id = $1`, id)
+}
+
+func (s *UpdateStorage) execDeadLetter(ctx context.Context, id int64, q string, args ...any) error {
+	tag, err := s.pool.Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to change dead letter %d: %w", id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %d", updateStorage.ErrDeadLetterNotFound, id)
+	}
+
+	return nil
+}
+
+func (s *UpdateStorage) deadLetters(ctx context.Context, q string, args ...any) ([]*model.DeadLetter, error) {
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*model.DeadLetter
+	for rows.Next() {
+		var (
+			dl     model.DeadLetter
+			status string
+		)
+
+		if err := rows.Scan(&dl.ID, &dl.UpdateID, &dl.UserID, &dl.ChatID, &dl.Update, &dl.Error,
+			&dl.Attempts, &status, &dl.CreatedAt, &dl.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+
+		dl.Status = model.DeadLetterStatus(status)
+		res = append(res, &dl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	return res, nil
+}
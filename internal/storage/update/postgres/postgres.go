@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"drillCore/internal/storage/pg"
+	updateStorage "drillCore/internal/storage/update"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// UpdateStorage — updateStorage.Storage on postgres
+type UpdateStorage struct {
+	pool   *pgxpool.Pool
+	logger *zap.SugaredLogger
+}
+
+func New(pool *pgxpool.Pool, logger *zap.SugaredLogger) *UpdateStorage {
+	return &UpdateStorage{pool: pool, logger: logger}
+}
+
+func (s *UpdateStorage) Offset(ctx context.Context) (int, error) {
+	q := `SELECT next_offset FROM polling_offset WHERE id = TRUE`
+
+	var offset int
+	if err := s.pool.QueryRow(ctx, q).Scan(&offset); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to get polling offset: %w", err)
+	}
+
+	return offset, nil
+}
+
+// IsProcessed — an update with an audit event is processed as well: its handler committed the side effect,
+// but the update itself was not committed before a crash or a failed Commit
+func (s *UpdateStorage) IsProcessed(ctx context.Context, updateID int) (bool, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM processed_update WHERE update_id = $1)
+		 OR EXISTS (SELECT 1 FROM audit_event WHERE update_id = $1)`
+
+	var res bool
+	if err := s.pool.QueryRow(ctx, q, updateID).Scan(&res); err != nil {
+		return false, fmt.Errorf("failed to check update %d: %w", updateID, err)
+	}
+
+	return res, nil
+}
+
+func (s *UpdateStorage) Commit(ctx context.Context, updateID int) error {
+	return pg.WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		return s.commit(ctx, tx, updateID)
+	})
+}
+
+func (s *UpdateStorage) commit(ctx context.Context, tx pgx.Tx, updateID int) error {
+	q := `INSERT INTO processed_update (update_id) VALUES ($1) ON CONFLICT (update_id) DO NOTHING`
+
+	if _, err := tx.Exec(ctx, q, updateID); err != nil {
+		return fmt.Errorf("failed to mark update %d processed: %w", updateID, err)
+	}
+
+	q = `INSERT INTO polling_offset (id, next_offset) VALUES (TRUE, $1)
+		 ON CONFLICT (id) DO UPDATE
+		 SET next_offset = GREATEST(polling_offset.next_offset, EXCLUDED.next_offset), updated_at = NOW()`
+
+	if _, err := tx.Exec(ctx, q, updateID+1); err != nil {
+		return fmt.Errorf("failed to save polling offset: %w", err)
+	}
+
+	q = `DELETE FROM processed_update WHERE processed_at < $1`
+
+	if _, err := tx.Exec(ctx, q, time.Now().Add(-updateStorage.Retention)); err != nil {
+		return fmt.Errorf("failed to prune processed updates: %w", err)
+	}
+
+	return nil
+}
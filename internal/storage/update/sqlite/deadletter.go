@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"drillCore/internal/model"
+	"drillCore/internal/storage/sqlitedb"
+	updateStorage "drillCore/internal/storage/update"
+)
+
+const deadLetterColumns = `id, update_id, user_id, chat_id, raw_update, error, attempts, status, created_at, updated_at`
+
+func (s *UpdateStorage) CommitDeadLetter(ctx context.Context, dl *model.DeadLetter) (int64, error) {
+	var id int64
+
+	err := sqlitedb.WithTx(ctx, s.conn, func(tx *sql.Tx) error {
+		q := `INSERT INTO dead_letter (update_id, user_id, chat_id, raw_update, error, attempts, status)
+			 VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+			 RETURNING id`
+
+		err := tx.QueryRowContext(ctx, q, dl.UpdateID, dl.UserID, dl.ChatID, string(updateStorage.RawUpdate(dl.Update)), dl.Error, dl.Attempts, string(model.DeadLetterFailed)).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to save dead letter: %w", err)
+		}
+
+		return s.commit(ctx, tx, int(dl.UpdateID))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *UpdateStorage) ReplayQueue(ctx context.Context, limit int) ([]*model.DeadLetter, error) {
+	q := `SELECT ` + deadLetterColumns + ` FROM dead_letter WHERE status = ?1 ORDER BY id LIMIT ?2`
+
+	return s.deadLetters(ctx, q, string(model.DeadLetterReplay), limit)
+}
+
+func (s *UpdateStorage) DeadLetters(ctx context.Context, limit int) ([]*model.DeadLetter, error) {
+	q := `SELECT ` + deadLetterColumns + ` FROM dead_letter ORDER BY id DESC LIMIT ?1`
+
+	return s.deadLetters(ctx, q, limit)
+}
+
+func (s *UpdateStorage) RequeueDeadLetter(ctx context.Context, id int64) error {
+	q := `UPDATE dead_letter SET status = ?2, updated_at = unixepoch() WHERE id = ?1`
+
+	return s.execDeadLetter(ctx, id, q, id, string(model.DeadLetterReplay))
+}
+
+func (s *UpdateStorage) FailDeadLetter(ctx context.Context, id int64, errText string, attempts int) error {
+	q := `UPDATE dead_letter SET status = ?2, error = ?3, attempts = attempts + ?4, updated_at = unixepoch() WHERE id = ?1`
+
+	return s.execDeadLetter(ctx, id, q, id, string(model.DeadLetterFailed), errText, attempts)
+}
+
+func (s *UpdateStorage) DeleteDeadLetter(ctx context.Context, id int64) error {
+	return s.execDeadLetter(ctx, id, `DELETE FROM dead_letter WHERE id = ?1`, id)
+}
+
+func (s *UpdateStorage) execDeadLetter(ctx context.Context, id int64, q string, args ...any) error {
+	res, err := s.conn.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to change dead letter %d: %w", id, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to change dead letter %d: %w", id, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%w: %d", updateStorage.ErrDeadLetterNotFound, id)
+	}
+
+	return nil
+}
+
+func (s *UpdateStorage) deadLetters(ctx context.Context, q string, args ...any) ([]*model.DeadLetter, error) {
+	rows, err := s.conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*model.DeadLetter
+	for rows.Next() {
+		var (
+			dl                   model.DeadLetter
+			raw, status          string
+			createdAt, updatedAt int64
+		)
+
+		if err := rows.Scan(&dl.ID, &dl.UpdateID, &dl.UserID, &dl.ChatID, &raw, &dl.Error,
+			&dl.Attempts, &status, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+
+		dl.Update = []byte(raw)
+		dl.Status = model.DeadLetterStatus(status)
+		dl.CreatedAt = time.Unix(createdAt, 0)
+		dl.UpdatedAt = time.Unix(updatedAt, 0)
+		res = append(res, &dl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"drillCore/internal/storage/sqlitedb"
+	updateStorage "drillCore/internal/storage/update"
+
+	"go.uber.org/zap"
+)
+
+// UpdateStorage — updateStorage.Storage on SQLite
+type UpdateStorage struct {
+	conn   *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func New(conn *sql.DB, logger *zap.SugaredLogger) *UpdateStorage {
+	return &UpdateStorage{conn: conn, logger: logger}
+}
+
+func (s *UpdateStorage) Offset(ctx context.Context) (int, error) {
+	q := `SELECT next_offset FROM polling_offset WHERE id = 1`
+
+	var offset int
+	if err := s.conn.QueryRowContext(ctx, q).Scan(&offset); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to get polling offset: %w", err)
+	}
+
+	return offset, nil
+}
+
+// IsProcessed — an update with an audit event is processed as well: its handler committed the side effect,
+// but the update itself was not committed before a crash or a failed Commit
+func (s *UpdateStorage) IsProcessed(ctx context.Context, updateID int) (bool, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM processed_update WHERE update_id = ?1)
+		 OR EXISTS (SELECT 1 FROM audit_event WHERE update_id = ?1)`
+
+	var res bool
+	if err := s.conn.QueryRowContext(ctx, q, updateID).Scan(&res); err != nil {
+		return false, fmt.Errorf("failed to check update %d: %w", updateID, err)
+	}
+
+	return res, nil
+}
+
+func (s *UpdateStorage) Commit(ctx context.Context, updateID int) error {
+	return sqlitedb.WithTx(ctx, s.conn, func(tx *sql.Tx) error {
+		return s.commit(ctx, tx, updateID)
+	})
+}
+
+func (s *UpdateStorage) commit(ctx context.Context, tx *sql.Tx, updateID int) error {
+	q := `INSERT INTO processed_update (update_id) VALUES (?1) ON CONFLICT (update_id) DO NOTHING`
+
+	if _, err := tx.ExecContext(ctx, q, updateID); err != nil {
+		return fmt.Errorf("failed to mark update %d processed: %w", updateID, err)
+	}
+
+	q = `INSERT INTO polling_offset (id, next_offset) VALUES (1, ?1)
+		 ON CONFLICT (id) DO UPDATE
+		 SET next_offset = MAX(next_offset, excluded.next_offset), updated_at = unixepoch()`
+
+	if _, err := tx.ExecContext(ctx, q, updateID+1); err != nil {
+		return fmt.Errorf("failed to save polling offset: %w", err)
+	}
+
+	q = `DELETE FROM processed_update WHERE processed_at < ?1`
+
+	if _, err := tx.ExecContext(ctx, q, time.Now().Add(-updateStorage.Retention).Unix()); err != nil {
+		return fmt.Errorf("failed to prune processed updates: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package updateStorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"drillCore/internal/model"
+)
+
+// Retention — how long processed update IDs are kept, telegram redelivers updates for 24 hours at most
+const Retention = 48 * time.Hour
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// Storage — the contract of every update storage backend: polling offset, processed update IDs and dead letters.
+// Calls on a missing dead letter are reported as ErrDeadLetterNotFound.
+type Storage interface {
+	// Offset — the next update ID to fetch, 0 before the first commit
+	Offset(ctx context.Context) (int, error)
+
+	// IsProcessed — the update was already committed or its handler already wrote its side effect,
+	// so a re-run does not repeat it. Committed IDs older than Retention may be forgotten.
+	IsProcessed(ctx context.Context, updateID int) (bool, error)
+
+	// Commit — marks the update processed and moves the offset past it atomically
+	Commit(ctx context.Context, updateID int) error
+
+	// CommitDeadLetter — saves the dead letter and commits its update atomically, returns the dead letter ID
+	CommitDeadLetter(ctx context.Context, dl *model.DeadLetter) (int64, error)
+
+	// ReplayQueue — dead letters queued for replay, oldest first
+	ReplayQueue(ctx context.Context, limit int) ([]*model.DeadLetter, error)
+
+	// DeadLetters — the latest dead letters, newest first
+	DeadLetters(ctx context.Context, limit int) ([]*model.DeadLetter, error)
+
+	// RequeueDeadLetter — queues the dead letter for replay on the next fetch
+	RequeueDeadLetter(ctx context.Context, id int64) error
+
+	// FailDeadLetter — records one more failed replay and returns the dead letter to the admins
+	FailDeadLetter(ctx context.Context, id int64, errText string, attempts int) error
+
+	// DeleteDeadLetter — removes a replayed or discarded dead letter
+	DeleteDeadLetter(ctx context.Context, id int64) error
+}
+
+// RawUpdate — the update to store in the dead letter, JSON null when it could not be marshalled,
+// such a dead letter replays as an unknown event
+func RawUpdate(raw []byte) []byte {
+	if len(raw) == 0 {
+		return []byte("null")
+	}
+
+	return raw
+}
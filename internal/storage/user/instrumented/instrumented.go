@@ -0,0 +1,101 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"drillCore/internal/metrics"
+	"drillCore/internal/model"
+	userStorage "drillCore/internal/storage/user"
+	"drillCore/internal/tracing"
+)
+
+// UserStorage — records the latency and a span of every call of the wrapped storage
+type UserStorage struct {
+	next userStorage.Storage
+}
+
+func New(next userStorage.Storage) *UserStorage {
+	return &UserStorage{next: next}
+}
+
+// begin — starts the span of operation, done records its latency and ends the span
+func begin(ctx context.Context, operation string) (context.Context, func(err error)) {
+	ctx, span := tracing.Start(ctx, "storage."+operation)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		metrics.StorageDuration.WithLabelValues(operation, metrics.Result(err)).Observe(metrics.Since(start))
+		tracing.End(span, err)
+	}
+}
+
+func (s *UserStorage) SaveUser(ctx context.Context, u *model.User) (err error) {
+	ctx, done := begin(ctx, "save_user")
+	defer func() { done(err) }()
+
+	return s.next.SaveUser(ctx, u)
+}
+
+func (s *UserStorage) User(ctx context.Context, userID int64) (u *model.User, err error) {
+	ctx, done := begin(ctx, "user")
+	defer func() { done(err) }()
+
+	return s.next.User(ctx, userID)
+}
+
+func (s *UserStorage) UserStats(ctx context.Context) (st *model.UserStats, err error) {
+	ctx, done := begin(ctx, "user_stats")
+	defer func() { done(err) }()
+
+	return s.next.UserStats(ctx)
+}
+
+func (s *UserStorage) CreateBroadcast(ctx context.Context, b *model.Broadcast) (res *model.Broadcast, err error) {
+	ctx, done := begin(ctx, "create_broadcast")
+	defer func() { done(err) }()
+
+	return s.next.CreateBroadcast(ctx, b)
+}
+
+func (s *UserStorage) Broadcast(ctx context.Context, id int64) (res *model.Broadcast, err error) {
+	ctx, done := begin(ctx, "broadcast")
+	defer func() { done(err) }()
+
+	return s.next.Broadcast(ctx, id)
+}
+
+func (s *UserStorage) Broadcasts(ctx context.Context, limit int) (res []*model.Broadcast, err error) {
+	ctx, done := begin(ctx, "broadcasts")
+	defer func() { done(err) }()
+
+	return s.next.Broadcasts(ctx, limit)
+}
+
+func (s *UserStorage) RunningBroadcasts(ctx context.Context) (res []*model.Broadcast, err error) {
+	ctx, done := begin(ctx, "running_broadcasts")
+	defer func() { done(err) }()
+
+	return s.next.RunningBroadcasts(ctx)
+}
+
+func (s *UserStorage) PendingDeliveries(ctx context.Context, broadcastID int64, limit int) (res []*model.Delivery, err error) {
+	ctx, done := begin(ctx, "pending_deliveries")
+	defer func() { done(err) }()
+
+	return s.next.PendingDeliveries(ctx, broadcastID, limit)
+}
+
+func (s *UserStorage) SaveDelivery(ctx context.Context, d *model.Delivery) (err error) {
+	ctx, done := begin(ctx, "save_delivery")
+	defer func() { done(err) }()
+
+	return s.next.SaveDelivery(ctx, d)
+}
+
+func (s *UserStorage) FinishBroadcast(ctx context.Context, id int64) (res *model.Broadcast, err error) {
+	ctx, done := begin(ctx, "finish_broadcast")
+	defer func() { done(err) }()
+
+	return s.next.FinishBroadcast(ctx, id)
+}
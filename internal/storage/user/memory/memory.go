@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"drillCore/internal/model"
+	userStorage "drillCore/internal/storage/user"
+)
+
+// UserStorage — in-memory userStorage.Storage, for demo mode
+type UserStorage struct {
+	mu sync.Mutex
+
+	users      map[int64]*model.User
+	broadcasts []*model.Broadcast
+	deliveries map[int64][]*model.Delivery // broadcast ID -> deliveries
+
+	now func() time.Time
+}
+
+func New() *UserStorage {
+	return &UserStorage{
+		users:      make(map[int64]*model.User),
+		deliveries: make(map[int64][]*model.Delivery),
+		now:        time.Now,
+	}
+}
+
+func (s *UserStorage) SaveUser(_ context.Context, u *model.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	saved, ok := s.users[u.ID]
+	if !ok {
+		saved = &model.User{ID: u.ID, FirstSeen: now}
+		s.users[u.ID] = saved
+	} else if !changed(saved, u) && now.Sub(saved.LastSeen) < userStorage.LastSeenPrecision {
+		return nil
+	}
+
+	saved.ChatID, saved.Blocked, saved.LastSeen = u.ChatID, false, now
+	saved.Username, saved.FirstName, saved.LastName = u.Username, u.FirstName, u.LastName
+	saved.LanguageCode = u.LanguageCode
+	if saved.Locale == "" {
+		saved.Locale = u.Locale
+	}
+	if saved.TimeZone == "" {
+		saved.TimeZone = u.TimeZone
+	}
+
+	return nil
+}
+
+// changed — the event brings something new to store besides last_seen
+func changed(saved, u *model.User) bool {
+	return saved.Blocked || saved.ChatID != u.ChatID ||
+		saved.Username != u.Username || saved.FirstName != u.FirstName || saved.LastName != u.LastName ||
+		saved.LanguageCode != u.LanguageCode ||
+		(saved.Locale == "" && u.Locale != "") || (saved.TimeZone == "" && u.TimeZone != "")
+}
+
+func (s *UserStorage) User(_ context.Context, userID int64) (*model.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", userStorage.ErrUserNotFound, userID)
+	}
+
+	res := *u
+	return &res, nil
+}
+
+func (s *UserStorage) UserStats(_ context.Context) (*model.UserStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := model.UserStats{Users: len(s.users)}
+	for _, u := range s.users {
+		if u.Blocked {
+			st.Blocked++
+		}
+	}
+
+	return &st, nil
+}
+
+func (s *UserStorage) CreateBroadcast(_ context.Context, b *model.Broadcast) (*model.Broadcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := &model.Broadcast{
+		ID:        int64(len(s.broadcasts) + 1),
+		Text:      b.Text,
+		CreatedBy: b.CreatedBy,
+		ChatID:    b.ChatID,
+		Status:    model.BroadcastRunning,
+		CreatedAt: s.now(),
+	}
+	s.broadcasts = append(s.broadcasts, saved)
+
+	for _, u := range s.users {
+		if !u.Blocked {
+			s.deliveries[saved.ID] = append(s.deliveries[saved.ID], &model.Delivery{
+				BroadcastID: saved.ID,
+				UserID:      u.ID,
+				ChatID:      u.ChatID,
+				Status:      model.DeliveryPending,
+			})
+		}
+	}
+
+	return s.counted(saved), nil
+}
+
+func (s *UserStorage) Broadcast(_ context.Context, id int64) (*model.Broadcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.broadcast(id)
+}
+
+func (s *UserStorage) Broadcasts(_ context.Context, limit int) ([]*model.Broadcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]*model.Broadcast, 0, limit)
+	for i := len(s.broadcasts) - 1; i >= 0 && len(res) < limit; i-- {
+		res = append(res, s.counted(s.broadcasts[i]))
+	}
+
+	return res, nil
+}
+
+func (s *UserStorage) RunningBroadcasts(_ context.Context) ([]*model.Broadcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*model.Broadcast
+	for _, b := range s.broadcasts {
+		if b.Status == model.BroadcastRunning {
+			res = append(res, s.counted(b))
+		}
+	}
+
+	return res, nil
+}
+
+func (s *UserStorage) PendingDeliveries(_ context.Context, broadcastID int64, limit int) ([]*model.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*model.Delivery
+	for _, d := range s.deliveries[broadcastID] {
+		if d.Status == model.DeliveryPending {
+			c := *d
+			res = append(res, &c)
+		}
+	}
+
+	slices.SortFunc(res, func(a, b *model.Delivery) int {
+		return cmp.Or(cmp.Compare(a.Attempts, b.Attempts), cmp.Compare(a.UserID, b.UserID))
+	})
+
+	return res[:min(len(res), limit)], nil
+}
+
+func (s *UserStorage) SaveDelivery(_ context.Context, d *model.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, saved := range s.deliveries[d.BroadcastID] {
+		if saved.UserID == d.UserID {
+			*saved = *d
+		}
+	}
+
+	if u, ok := s.users[d.UserID]; ok && d.Status == model.DeliveryBlocked {
+		u.Blocked = true
+	}
+
+	return nil
+}
+
+func (s *UserStorage) FinishBroadcast(_ context.Context, id int64) (*model.Broadcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := s.broadcast(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	saved := s.broadcasts[id-1]
+	saved.Status, saved.FinishedAt = model.BroadcastDone, &now
+	b.Status, b.FinishedAt = saved.Status, saved.FinishedAt
+
+	return b, nil
+}
+
+func (s *UserStorage) broadcast(id int64) (*model.Broadcast, error) {
+	if id < 1 || id > int64(len(s.broadcasts)) {
+		return nil, fmt.Errorf("broadcast %d not found", id)
+	}
+
+	return s.counted(s.broadcasts[id-1]), nil
+}
+
+// counted — copy of the broadcast with the counters of its deliveries
+func (s *UserStorage) counted(b *model.Broadcast) *model.Broadcast {
+	c := *b
+	for _, d := range s.deliveries[b.ID] {
+		c.Total++
+
+		switch d.Status {
+		case model.DeliveryPending:
+			c.Pending++
+		case model.DeliverySent:
+			c.Sent++
+		case model.DeliveryFailed:
+			c.Failed++
+		case model.DeliveryBlocked:
+			c.Blocked++
+		case model.DeliverySkipped:
+			c.Skipped++
+		}
+	}
+
+	return &c
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"drillCore/internal/model"
+	userStorage "drillCore/internal/storage/user"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// UserStorage — userStorage.Storage on postgres
+type UserStorage struct {
+	pool   *pgxpool.Pool
+	logger *zap.SugaredLogger
+}
+
+func New(pool *pgxpool.Pool, logger *zap.SugaredLogger) *UserStorage {
+	return &UserStorage{pool: pool, logger: logger}
+}
+
+func (s *UserStorage) SaveUser(ctx context.Context, u *model.User) error {
+	q := `INSERT INTO users (user_id, chat_id, username, first_name, last_name, language_code, locale, time_zone, last_seen)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET chat_id = EXCLUDED.chat_id,
+		     username = EXCLUDED.username,
+		     first_name = EXCLUDED.first_name,
+		     last_name = EXCLUDED.last_name,
+		     language_code = EXCLUDED.language_code,
+		     locale = CASE WHEN users.locale = '' THEN EXCLUDED.locale ELSE users.locale END,
+		     time_zone = CASE WHEN users.time_zone = '' THEN EXCLUDED.time_zone ELSE users.time_zone END,
+		     blocked = FALSE,
+		     blocked_at = NULL,
+		     last_seen = EXCLUDED.last_seen
+		 WHERE users.last_seen < NOW() - make_interval(secs => $9)
+		    OR users.blocked
+		    OR users.chat_id <> EXCLUDED.chat_id
+		    OR users.username <> EXCLUDED.username
+		    OR users.first_name <> EXCLUDED.first_name
+		    OR users.last_name <> EXCLUDED.last_name
+		    OR users.language_code <> EXCLUDED.language_code
+		    OR (users.locale = '' AND EXCLUDED.locale <> '')
+		    OR (users.time_zone = '' AND EXCLUDED.time_zone <> '')`
+
+	_, err := s.pool.Exec(ctx, q, u.ID, u.ChatID, u.Username, u.FirstName, u.LastName, u.LanguageCode, u.Locale, u.TimeZone,
+		userStorage.LastSeenPrecision.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to save user %d: %w", u.ID, err)
+	}
+
+	return nil
+}
+
+func (s *UserStorage) User(ctx context.Context, userID int64) (*model.User, error) {
+	q := `SELECT user_id, chat_id, username, first_name, last_name, language_code, locale, time_zone,
+		        blocked, first_seen, last_seen
+		 FROM users WHERE user_id = $1`
+
+	var u model.User
+	err := s.pool.QueryRow(ctx, q, userID).Scan(
+		&u.ID, &u.ChatID, &u.Username, &u.FirstName, &u.LastName, &u.LanguageCode, &u.Locale, &u.TimeZone,
+		&u.Blocked, &u.FirstSeen, &u.LastSeen,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", userStorage.ErrUserNotFound, userID)
+		}
+		return nil, fmt.Errorf("failed to get user %d: %w", userID, err)
+	}
+
+	return &u, nil
+}
+
+func (s *UserStorage) UserStats(ctx context.Context) (*model.UserStats, error) {
+	q := `SELECT COUNT(*), COUNT(*) FILTER (WHERE blocked) FROM users`
+
+	var st model.UserStats
+	if err := s.pool.QueryRow(ctx, q).Scan(&st.Users, &st.Blocked); err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &st, nil
+}
@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"drillCore/internal/model"
+	"drillCore/internal/storage/sqlitedb"
+)
+
+// broadcastSelect — broadcasts with the counters of their deliveries
+const broadcastSelect = `SELECT b.id, b.text, b.created_by, b.chat_id, b.status, b.created_at, b.finished_at,
+		COUNT(d.user_id),
+		COUNT(*) FILTER (WHERE d.status = 'pending'),
+		COUNT(*) FILTER (WHERE d.status = 'sent'),
+		COUNT(*) FILTER (WHERE d.status = 'failed'),
+		COUNT(*) FILTER (WHERE d.status = 'blocked'),
+		COUNT(*) FILTER (WHERE d.status = 'skipped')
+	 FROM broadcast b
+	 LEFT JOIN broadcast_delivery d ON d.broadcast_id = b.id`
+
+func (s *UserStorage) CreateBroadcast(ctx context.Context, b *model.Broadcast) (*model.Broadcast, error) {
+	var id int64
+
+	err := sqlitedb.WithTx(ctx, s.conn, func(tx *sql.Tx) error {
+		q := `INSERT INTO broadcast (text, created_by, chat_id, status) VALUES (?1, ?2, ?3, ?4) RETURNING id`
+
+		if err := tx.QueryRowContext(ctx, q, b.Text, b.CreatedBy, b.ChatID, string(model.BroadcastRunning)).Scan(&id); err != nil {
+			return fmt.Errorf("failed to save broadcast: %w", err)
+		}
+
+		q = `INSERT INTO broadcast_delivery (broadcast_id, user_id, chat_id, status)
+			 SELECT ?1, user_id, chat_id, ?2 FROM users WHERE NOT blocked`
+
+		if _, err := tx.ExecContext(ctx, q, id, string(model.DeliveryPending)); err != nil {
+			return fmt.Errorf("failed to save broadcast deliveries: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Broadcast(ctx, id)
+}
+
+func (s *UserStorage) Broadcast(ctx context.Context, id int64) (*model.Broadcast, error) {
+	res, err := s.broadcasts(ctx, broadcastSelect+` WHERE b.id = ?1 GROUP BY b.id`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("broadcast %d not found", id)
+	}
+
+	return res[0], nil
+}
+
+func (s *UserStorage) Broadcasts(ctx context.Context, limit int) ([]*model.Broadcast, error) {
+	return s.broadcasts(ctx, broadcastSelect+` GROUP BY b.id ORDER BY b.id DESC LIMIT ?1`, limit)
+}
+
+func (s *UserStorage) RunningBroadcasts(ctx context.Context) ([]*model.Broadcast, error) {
+	return s.broadcasts(ctx, broadcastSelect+` WHERE b.status = ?1 GROUP BY b.id ORDER BY b.id`, string(model.BroadcastRunning))
+}
+
+func (s *UserStorage) PendingDeliveries(ctx context.Context, broadcastID int64, limit int) ([]*model.Delivery, error) {
+	q := `SELECT broadcast_id, user_id, chat_id, status, attempts, error
+		 FROM broadcast_delivery
+		 WHERE broadcast_id = ?1 AND status = ?2
+		 ORDER BY attempts, user_id
+		 LIMIT ?3`
+
+	rows, err := s.conn.QueryContext(ctx, q, broadcastID, string(model.DeliveryPending), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*model.Delivery
+	for rows.Next() {
+		var d model.Delivery
+		var status string
+
+		if err := rows.Scan(&d.BroadcastID, &d.UserID, &d.ChatID, &status, &d.Attempts, &d.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+
+		d.Status = model.DeliveryStatus(status)
+		res = append(res, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pending deliveries: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *UserStorage) SaveDelivery(ctx context.Context, d *model.Delivery) error {
+	return sqlitedb.WithTx(ctx, s.conn, func(tx *sql.Tx) error {
+		q := `UPDATE broadcast_delivery SET status = ?3, attempts = ?4, error = ?5, updated_at = unixepoch()
+			 WHERE broadcast_id = ?1 AND user_id = ?2`
+
+		if _, err := tx.ExecContext(ctx, q, d.BroadcastID, d.UserID, string(d.Status), d.Attempts, d.Error); err != nil {
+			return fmt.Errorf("failed to save delivery to user %d: %w", d.UserID, err)
+		}
+
+		if d.Status != model.DeliveryBlocked {
+			return nil
+		}
+
+		q = `UPDATE users SET blocked = 1, blocked_at = unixepoch() WHERE user_id = ?1`
+
+		if _, err := tx.ExecContext(ctx, q, d.UserID); err != nil {
+			return fmt.Errorf("failed to block user %d: %w", d.UserID, err)
+		}
+
+		return nil
+	})
+}
+
+func (s *UserStorage) FinishBroadcast(ctx context.Context, id int64) (*model.Broadcast, error) {
+	q := `UPDATE broadcast SET status = ?2, finished_at = unixepoch() WHERE id = ?1`
+
+	if _, err := s.conn.ExecContext(ctx, q, id, string(model.BroadcastDone)); err != nil {
+		return nil, fmt.Errorf("failed to finish broadcast %d: %w", id, err)
+	}
+
+	return s.Broadcast(ctx, id)
+}
+
+func (s *UserStorage) broadcasts(ctx context.Context, q string, args ...any) ([]*model.Broadcast, error) {
+	rows, err := s.conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcasts: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*model.Broadcast
+	for rows.Next() {
+		var b model.Broadcast
+		var status string
+		var createdAt int64
+		var finishedAt sql.NullInt64
+
+		err := rows.Scan(&b.ID, &b.Text, &b.CreatedBy, &b.ChatID, &status, &createdAt, &finishedAt,
+			&b.Total, &b.Pending, &b.Sent, &b.Failed, &b.Blocked, &b.Skipped)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan broadcast: %w", err)
+		}
+
+		b.Status = model.BroadcastStatus(status)
+		b.CreatedAt = time.Unix(createdAt, 0)
+		if finishedAt.Valid {
+			t := time.Unix(finishedAt.Int64, 0)
+			b.FinishedAt = &t
+		}
+
+		res = append(res, &b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read broadcasts: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"drillCore/internal/model"
+	userStorage "drillCore/internal/storage/user"
+
+	"go.uber.org/zap"
+)
+
+// UserStorage — userStorage.Storage on SQLite
+type UserStorage struct {
+	conn   *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func New(conn *sql.DB, logger *zap.SugaredLogger) *UserStorage {
+	return &UserStorage{conn: conn, logger: logger}
+}
+
+func (s *UserStorage) SaveUser(ctx context.Context, u *model.User) error {
+	q := `INSERT INTO users (user_id, chat_id, username, first_name, last_name, language_code, locale, time_zone, last_seen)
+		 VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, unixepoch())
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET chat_id = excluded.chat_id,
+		     username = excluded.username,
+		     first_name = excluded.first_name,
+		     last_name = excluded.last_name,
+		     language_code = excluded.language_code,
+		     locale = CASE WHEN users.locale = '' THEN excluded.locale ELSE users.locale END,
+		     time_zone = CASE WHEN users.time_zone = '' THEN excluded.time_zone ELSE users.time_zone END,
+		     blocked = 0,
+		     blocked_at = NULL,
+		     last_seen = excluded.last_seen
+		 WHERE users.last_seen < unixepoch() - ?9
+		    OR users.blocked
+		    OR users.chat_id <> excluded.chat_id
+		    OR users.username <> excluded.username
+		    OR users.first_name <> excluded.first_name
+		    OR users.last_name <> excluded.last_name
+		    OR users.language_code <> excluded.language_code
+		    OR (users.locale = '' AND excluded.locale <> '')
+		    OR (users.time_zone = '' AND excluded.time_zone <> '')`
+
+	_, err := s.conn.ExecContext(ctx, q, u.ID, u.ChatID, u.Username, u.FirstName, u.LastName, u.LanguageCode, u.Locale, u.TimeZone,
+		int64(userStorage.LastSeenPrecision.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to save user %d: %w", u.ID, err)
+	}
+
+	return nil
+}
+
+func (s *UserStorage) User(ctx context.Context, userID int64) (*model.User, error) {
+	q := `SELECT user_id, chat_id, username, first_name, last_name, language_code, locale, time_zone,
+		        blocked, first_seen, last_seen
+		 FROM users WHERE user_id = ?1`
+
+	var (
+		u                   model.User
+		firstSeen, lastSeen int64
+	)
+	err := s.conn.QueryRowContext(ctx, q, userID).Scan(
+		&u.ID, &u.ChatID, &u.Username, &u.FirstName, &u.LastName, &u.LanguageCode, &u.Locale, &u.TimeZone,
+		&u.Blocked, &firstSeen, &lastSeen,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", userStorage.ErrUserNotFound, userID)
+		}
+		return nil, fmt.Errorf("failed to get user %d: %w", userID, err)
+	}
+	u.FirstSeen, u.LastSeen = time.Unix(firstSeen, 0), time.Unix(lastSeen, 0)
+
+	return &u, nil
+}
+
+func (s *UserStorage) UserStats(ctx context.Context) (*model.UserStats, error) {
+	q := `SELECT COUNT(*), COUNT(*) FILTER (WHERE blocked) FROM users`
+
+	var st model.UserStats
+	if err := s.conn.QueryRowContext(ctx, q).Scan(&st.Users, &st.Blocked); err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &st, nil
+}
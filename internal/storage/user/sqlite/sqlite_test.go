@@ -0,0 +1,103 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"drillCore/internal/config"
+	"drillCore/internal/model"
+	"drillCore/internal/storage/migrate"
+	"drillCore/internal/storage/sqlitedb"
+	"drillCore/internal/storage/user/sqlite"
+	"drillCore/migrations"
+
+	"go.uber.org/zap"
+)
+
+// newStorage — storage on a migrated database in a temp file, with the database to tweak the rows
+func newStorage(t *testing.T) (*sqlite.UserStorage, *sql.DB) {
+	logger := zap.NewNop().Sugar()
+
+	cfg := &config.DbEnvs{
+		Driver:           config.DriverSQLite,
+		Path:             filepath.Join(t.TempDir(), "drillcore.db"),
+		StatementTimeout: 5 * time.Second,
+	}
+
+	db, err := sqlitedb.Open(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrate.NewSQLite(db, migrations.SQLite(), logger)
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+
+	if err := m.Up(t.Context()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return sqlite.New(db, logger), db
+}
+
+// TestSaveUserLastSeen — an unchanged user is written at most once per LastSeenPrecision
+func TestSaveUserLastSeen(t *testing.T) {
+	s, db := newStorage(t)
+	ctx := t.Context()
+
+	u := &model.User{ID: 1, ChatID: 1, FirstName: "Simon", LanguageCode: "en", Locale: "en", TimeZone: "UTC"}
+
+	// seenAgo — moves last_seen of the user into the past and returns it
+	seenAgo := func(ago time.Duration) time.Time {
+		t.Helper()
+
+		seen := time.Now().Add(-ago).Truncate(time.Second)
+		if _, err := db.ExecContext(ctx, `UPDATE users SET last_seen = ?1 WHERE user_id = ?2`, seen.Unix(), u.ID); err != nil {
+			t.Fatalf("set last seen: %v", err)
+		}
+
+		return seen
+	}
+
+	cases := []struct {
+		name      string
+		ago       time.Duration
+		firstName string
+		written   bool
+	}{
+		{"unchanged and seen recently", 30 * time.Second, "Simon", false},
+		{"unchanged and seen long ago", 2 * time.Minute, "Simon", true},
+		{"changed and seen recently", 30 * time.Second, "Kamina", true},
+	}
+
+	if err := s.SaveUser(ctx, u); err != nil {
+		t.Fatalf("save user: %v", err)
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seen := seenAgo(tc.ago)
+
+			u.FirstName = tc.firstName
+			if err := s.SaveUser(ctx, u); err != nil {
+				t.Fatalf("save user: %v", err)
+			}
+
+			got, err := s.User(ctx, u.ID)
+			if err != nil {
+				t.Fatalf("user: %v", err)
+			}
+
+			if written := !got.LastSeen.Equal(seen); written != tc.written {
+				t.Fatalf("last seen %v (was %v), want written: %v", got.LastSeen, seen, tc.written)
+			}
+			if got.FirstName != tc.firstName {
+				t.Fatalf("first name %q, want %q", got.FirstName, tc.firstName)
+			}
+		})
+	}
+}
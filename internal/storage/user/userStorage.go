@@ -0,0 +1,49 @@
+package userStorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"drillCore/internal/model"
+)
+
+// LastSeenPrecision — last_seen of an unchanged user is refreshed at most this often,
+// so an active user does not cost a write on every event
+const LastSeenPrecision = time.Minute
+
+var ErrUserNotFound = errors.New("user not found")
+
+// Storage — the contract of every user storage backend: the users registry and the broadcasts to them
+type Storage interface {
+	// SaveUser — upserts the user, a blocked user who writes again is unblocked. Locale and time zone
+	// are kept once guessed. An unchanged user is written only when last_seen is older than LastSeenPrecision.
+	SaveUser(ctx context.Context, u *model.User) error
+
+	// User — the saved user, ErrUserNotFound when the user never wrote to the bot
+	User(ctx context.Context, userID int64) (*model.User, error)
+
+	// UserStats — totals over every user
+	UserStats(ctx context.Context) (*model.UserStats, error)
+
+	// CreateBroadcast — saves the broadcast with a pending delivery for every user who did not block the bot
+	CreateBroadcast(ctx context.Context, b *model.Broadcast) (*model.Broadcast, error)
+
+	// Broadcast — the broadcast with the counters of its deliveries
+	Broadcast(ctx context.Context, id int64) (*model.Broadcast, error)
+
+	// Broadcasts — the latest broadcasts, newest first
+	Broadcasts(ctx context.Context, limit int) ([]*model.Broadcast, error)
+
+	// RunningBroadcasts — broadcasts with deliveries left, oldest first
+	RunningBroadcasts(ctx context.Context) ([]*model.Broadcast, error)
+
+	// PendingDeliveries — deliveries of the broadcast still to be sent, the retried ones go last
+	PendingDeliveries(ctx context.Context, broadcastID int64, limit int) ([]*model.Delivery, error)
+
+	// SaveDelivery — records the result of the delivery, a blocked delivery blocks the user as well
+	SaveDelivery(ctx context.Context, d *model.Delivery) error
+
+	// FinishBroadcast — marks the broadcast done and returns its final counters
+	FinishBroadcast(ctx context.Context, id int64) (*model.Broadcast, error)
+}
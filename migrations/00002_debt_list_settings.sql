@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS debt_list_settings (
+user_id BIGINT PRIMARY KEY,
+sort SMALLINT NOT NULL DEFAULT 0,
+filter SMALLINT NOT NULL DEFAULT 0,
+updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS debt_user_id_return_date_idx ON debt(user_id, return_date);
+
+-- +goose Down
+DROP INDEX IF EXISTS debt_user_id_return_date_idx;
+DROP TABLE IF EXISTS debt_list_settings;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE debt
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+-- +goose Down
+ALTER TABLE debt
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS version;
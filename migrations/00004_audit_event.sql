@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS audit_event (
+id BIGSERIAL PRIMARY KEY,
+user_id BIGINT NOT NULL,
+debt_id BIGINT NOT NULL,
+action TEXT NOT NULL,
+before JSONB,
+after JSONB,
+update_id BIGINT,
+created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_user_id_created_at_idx ON audit_event(user_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS audit_event_user_id_created_at_idx;
+DROP TABLE IF EXISTS audit_event;
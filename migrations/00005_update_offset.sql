@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS polling_offset (
+id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+next_offset BIGINT NOT NULL,
+updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS processed_update (
+update_id BIGINT PRIMARY KEY,
+processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS processed_update_processed_at_idx ON processed_update(processed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS processed_update_processed_at_idx;
+DROP TABLE IF EXISTS processed_update;
+DROP TABLE IF EXISTS polling_offset;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dead_letter (
+id BIGSERIAL PRIMARY KEY,
+update_id BIGINT NOT NULL,
+user_id BIGINT NOT NULL,
+chat_id BIGINT NOT NULL,
+raw_update JSONB NOT NULL,
+error TEXT NOT NULL,
+attempts INT NOT NULL,
+status TEXT NOT NULL DEFAULT 'failed',
+created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS dead_letter_status_idx ON dead_letter(status, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS dead_letter_status_idx;
+DROP TABLE IF EXISTS dead_letter;
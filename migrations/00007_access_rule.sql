@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS access_rule (
+user_id BIGINT PRIMARY KEY,
+allowed BOOLEAN NOT NULL DEFAULT FALSE,
+banned BOOLEAN NOT NULL DEFAULT FALSE,
+updated_by BIGINT NOT NULL,
+updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS access_rule;
@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+user_id BIGINT PRIMARY KEY,
+chat_id BIGINT NOT NULL,
+blocked BOOLEAN NOT NULL DEFAULT FALSE,
+first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+blocked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- users known before the registry, the private chat ID equals the user ID
+INSERT INTO users (user_id, chat_id)
+SELECT user_id, user_id FROM debt
+UNION SELECT user_id, user_id FROM audit_event
+UNION SELECT user_id, user_id FROM debt_list_settings
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS broadcast (
+id BIGSERIAL PRIMARY KEY,
+text TEXT NOT NULL,
+created_by BIGINT NOT NULL,
+chat_id BIGINT NOT NULL,
+status TEXT NOT NULL,
+created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS broadcast_delivery (
+broadcast_id BIGINT NOT NULL REFERENCES broadcast(id) ON DELETE CASCADE,
+user_id BIGINT NOT NULL,
+chat_id BIGINT NOT NULL,
+status TEXT NOT NULL,
+attempts INTEGER NOT NULL DEFAULT 0,
+error TEXT NOT NULL DEFAULT '',
+updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+PRIMARY KEY (broadcast_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS broadcast_delivery_status_idx ON broadcast_delivery(broadcast_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS broadcast_delivery_status_idx;
+DROP TABLE IF EXISTS broadcast_delivery;
+DROP TABLE IF EXISTS broadcast;
+DROP TABLE IF EXISTS users;
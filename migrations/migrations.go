@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS — goose-formatted SQL migrations, applied by the app on boot (see internal/storage/migrate)
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite — the same schema for the SQLite backend, versioned separately from FS
+func SQLite() fs.FS {
+	sub, err := fs.Sub(sqliteFS, "sqlite")
+	if err != nil {
+		panic(err)
+	}
+
+	return sub
+}
@@ -0,0 +1,26 @@
+-- +goose Up
+-- times are stored as unix seconds
+CREATE TABLE IF NOT EXISTS debt (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+description TEXT,
+amount INTEGER NOT NULL CHECK (amount > 0),
+return_date INTEGER,
+version INTEGER NOT NULL DEFAULT 1,
+created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS debt_user_id_idx ON debt(user_id);
+CREATE INDEX IF NOT EXISTS debt_user_id_return_date_idx ON debt(user_id, return_date);
+
+CREATE TABLE IF NOT EXISTS debt_list_settings (
+user_id INTEGER PRIMARY KEY,
+sort INTEGER NOT NULL DEFAULT 0,
+filter INTEGER NOT NULL DEFAULT 0,
+updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS debt_list_settings;
+DROP TABLE IF EXISTS debt;
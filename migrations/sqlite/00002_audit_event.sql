@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS audit_event (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+debt_id INTEGER NOT NULL,
+action TEXT NOT NULL,
+before TEXT,
+after TEXT,
+update_id INTEGER,
+created_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_user_id_created_at_idx ON audit_event(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_event_debt_id_idx ON audit_event(debt_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_event;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS polling_offset (
+id INTEGER PRIMARY KEY CHECK (id = 1),
+next_offset INTEGER NOT NULL,
+updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE TABLE IF NOT EXISTS processed_update (
+update_id INTEGER PRIMARY KEY,
+processed_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS processed_update_processed_at_idx ON processed_update(processed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS processed_update;
+DROP TABLE IF EXISTS polling_offset;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dead_letter (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+update_id INTEGER NOT NULL,
+user_id INTEGER NOT NULL,
+chat_id INTEGER NOT NULL,
+raw_update TEXT NOT NULL,
+error TEXT NOT NULL,
+attempts INTEGER NOT NULL,
+status TEXT NOT NULL DEFAULT 'failed',
+created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS dead_letter_status_idx ON dead_letter(status, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS dead_letter;
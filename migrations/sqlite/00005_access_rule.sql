@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS access_rule (
+user_id INTEGER PRIMARY KEY,
+allowed INTEGER NOT NULL DEFAULT 0,
+banned INTEGER NOT NULL DEFAULT 0,
+updated_by INTEGER NOT NULL,
+updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS access_rule;
@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+user_id INTEGER PRIMARY KEY,
+chat_id INTEGER NOT NULL,
+blocked INTEGER NOT NULL DEFAULT 0,
+first_seen INTEGER NOT NULL DEFAULT (unixepoch()),
+blocked_at INTEGER
+);
+
+-- users known before the registry, the private chat ID equals the user ID
+INSERT OR IGNORE INTO users (user_id, chat_id)
+SELECT user_id, user_id FROM debt
+UNION SELECT user_id, user_id FROM audit_event
+UNION SELECT user_id, user_id FROM debt_list_settings;
+
+CREATE TABLE IF NOT EXISTS broadcast (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+text TEXT NOT NULL,
+created_by INTEGER NOT NULL,
+chat_id INTEGER NOT NULL,
+status TEXT NOT NULL,
+created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+finished_at INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS broadcast_delivery (
+broadcast_id INTEGER NOT NULL REFERENCES broadcast(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL,
+chat_id INTEGER NOT NULL,
+status TEXT NOT NULL,
+attempts INTEGER NOT NULL DEFAULT 0,
+error TEXT NOT NULL DEFAULT '',
+updated_at INTEGER NOT NULL DEFAULT (unixepoch()),
+PRIMARY KEY (broadcast_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS broadcast_delivery_status_idx ON broadcast_delivery(broadcast_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS broadcast_delivery_status_idx;
+DROP TABLE IF EXISTS broadcast_delivery;
+DROP TABLE IF EXISTS broadcast;
+DROP TABLE IF EXISTS users;
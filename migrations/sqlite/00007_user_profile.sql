@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN username TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN first_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN last_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN language_code TEXT NOT NULL DEFAULT '';
+-- guessed from language_code on the first contact, empty until the user writes again
+ALTER TABLE users ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+-- a column added later can not default to unixepoch()
+ALTER TABLE users ADD COLUMN last_seen INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users SET last_seen = first_seen;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN last_seen;
+ALTER TABLE users DROP COLUMN time_zone;
+ALTER TABLE users DROP COLUMN locale;
+ALTER TABLE users DROP COLUMN language_code;
+ALTER TABLE users DROP COLUMN last_name;
+ALTER TABLE users DROP COLUMN first_name;
+ALTER TABLE users DROP COLUMN username;
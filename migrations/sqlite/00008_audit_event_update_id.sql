@@ -0,0 +1,11 @@
+-- +goose Up
+-- one audit event per update, so a re-run of a handled update is detected (see update storage IsProcessed).
+-- Duplicates written before the index lose their update_id, the first one keeps it.
+UPDATE audit_event SET update_id = NULL
+WHERE update_id IS NOT NULL
+  AND id NOT IN (SELECT MIN(id) FROM audit_event WHERE update_id IS NOT NULL GROUP BY update_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS audit_event_update_id_idx ON audit_event(update_id) WHERE update_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS audit_event_update_id_idx;